@@ -0,0 +1,5 @@
+data "pgrmongodb_appfunction" "shared" {
+	project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+	appservices_app_id = "<MONGODB ATLAS APP SERVICES APP ID>"
+	function_name = "my_shared_function"
+}
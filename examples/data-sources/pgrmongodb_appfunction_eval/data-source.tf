@@ -0,0 +1,12 @@
+data "pgrmongodb_appfunction_eval" "smoke_check" {
+	project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+	appservices_app_id = pgrmongodb_appservicesapp.app.id
+	function_code = <<EOT
+exports = async (collectionName) => {
+	const count = await context.services.get("mongodb-atlas").db("app").collection(collectionName).count();
+	return { count };
+}
+EOT
+	function_args_json = [jsonencode("orders")]
+	execution_timeout = 30
+}
@@ -0,0 +1,9 @@
+data "pgrmongodb_appfunctiondependencies" "app" {
+	project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+	appservices_app_id = "<MONGODB ATLAS APP SERVICES APP ID>"
+}
+
+# name@version of every installed package, including transitive ones, for license and vulnerability scanning
+output "npm_packages" {
+	value = [for p in data.pgrmongodb_appfunctiondependencies.app.packages : "${p.name}@${p.version}"]
+}
@@ -0,0 +1,6 @@
+data "pgrmongodb_appfunctions" "all" {
+	project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+	appservices_app_id = "<MONGODB ATLAS APP SERVICES APP ID>"
+	name_prefix = "orders_"
+	name_regex = "_trigger$"
+}
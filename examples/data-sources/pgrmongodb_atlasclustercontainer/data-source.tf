@@ -2,3 +2,10 @@ data "pgrmongodb_atlasclustercontainer" "containers" {
   project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
   cloud_provider = "<AWS | AZURE | GCP>"
 }
+
+# the container serving a single region, the Azure spelling eastus2 is accepted as well as US_EAST_2
+data "pgrmongodb_atlasclustercontainer" "eastus2" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  cloud_provider = "AZURE"
+  region = "eastus2"
+}
@@ -0,0 +1,5 @@
+data "pgrmongodb_networkpeering" "aws" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  cloud_provider = "AWS"
+  container_id = data.pgrmongodb_atlasclustercontainer.aws.ids["AWS:US_EAST_1"]
+}
@@ -0,0 +1,12 @@
+ephemeral "pgrmongodb_appfunctionexecute" "api_key" {
+	project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+	appservices_app_id = pgrmongodb_appservicesapp.app.id
+	function_name = "generate_api_key"
+	function_args_json = [jsonencode("billing-service")]
+}
+
+resource "aws_secretsmanager_secret_version" "api_key" {
+	secret_id = aws_secretsmanager_secret.api_key.id
+	secret_string_wo = jsondecode(ephemeral.pgrmongodb_appfunctionexecute.api_key.result_json).key
+	secret_string_wo_version = 1
+}
@@ -8,3 +8,23 @@ exports = async (changeEvent) => {
 }
 EOT
 }
+
+resource "pgrmongodb_appfunction" "appfunction_typescript" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  appservices_app_id = pgrmongodb_appservicesapp.app.id
+  function_name = "my_terraform_mongodbatlas_typescript_function"
+  language = "typescript"
+  function_code = <<EOT
+exports = async (changeEvent: any): Promise<void> => {
+	const greeting: string = 'Hello World!';
+	console.log(greeting);
+}
+EOT
+}
+
+resource "pgrmongodb_appfunction" "appfunction_bundled" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  appservices_app_id = pgrmongodb_appservicesapp.app.id
+  function_name = "my_terraform_mongodbatlas_bundled_function"
+  entrypoint = "${path.module}/functions/my_bundled_function.js"
+}
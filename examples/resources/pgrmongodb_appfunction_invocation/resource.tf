@@ -0,0 +1,20 @@
+resource "pgrmongodb_appfunction_invocation" "create_indexes" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  appservices_app_id = pgrmongodb_appservicesapp.app.id
+  function_name = pgrmongodb_appfunction.appfunction.function_name
+  function_args_json = [jsonencode({ collection = "orders" })]
+  triggers = {
+    function_code_hash = pgrmongodb_appfunction.appfunction.function_code_hash
+  }
+  destroy_function_name = "drop_indexes"
+  destroy_function_args_json = [jsonencode({ collection = "orders" })]
+  execution_timeout = 60
+  max_attempts = 3
+  retry_on = ["timeout", "5xx"]
+  retry_delay = "30s"
+
+  expect {
+    path = "$.created"
+    greater_than = 0
+  }
+}
@@ -0,0 +1,3 @@
+# Function dependencies can be imported with project_id,appservices_app_id. Every installed package is imported and
+# mode is taken from the configuration, so with mode = "additive" the next apply only manages the declared packages
+terraform import pgrmongodb_appfunctiondependencies.appfunctiondependencies 5f1a0f0b4e2c3d0a1b2c3d4e,5f1a0f0b4e2c3d0a1b2c3d4f
@@ -2,7 +2,31 @@ resource "pgrmongodb_appfunctiondependencies" "appfunctiondependencies" {
   project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
   appservices_app_id = pgrmongodb_appservicesapp.app.id
   dependencies = [
-    "simple-test-package 0.2.2",
-    "uuidv1 1.6.14"
+    { name = "simple-test-package", version = "0.2.2" },
+    { name = "uuidv1", version = "1.6.14" },
+    { name = "@turf/helpers", version = "7.1.0" },
   ]
 }
+
+# manage only the packages this configuration needs, leaving packages added by others in place
+resource "pgrmongodb_appfunctiondependencies" "reporting" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  appservices_app_id = pgrmongodb_appservicesapp.shared_app.id
+  mode = "additive"
+  dependencies = [
+    # ranges and dist-tags are resolved against the npm registry on every plan
+    { name = "date-fns", version = "^3.6.0" },
+  ]
+}
+
+# install everything listed in a package.json in a single build
+resource "pgrmongodb_appfunctiondependencies" "from_package_json" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  appservices_app_id = pgrmongodb_appservicesapp.other_app.id
+  package_json = file("${path.module}/functions/package.json")
+
+  timeouts {
+    create = "45m"
+    update = "45m"
+  }
+}
@@ -0,0 +1,6 @@
+resource "pgrmongodb_appfunctions" "appfunctions" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  appservices_app_id = pgrmongodb_appservicesapp.app.id
+  source_dir = "${path.module}/functions"
+  purge_unmanaged = true
+}
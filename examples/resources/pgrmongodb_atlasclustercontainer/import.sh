@@ -0,0 +1,2 @@
+# Network containers can be imported with project_id,container_id
+terraform import pgrmongodb_atlasclustercontainer.aws 5f1a0f0b4e2c3d0a1b2c3d4e,5f1a0f0b4e2c3d0a1b2c3d4f
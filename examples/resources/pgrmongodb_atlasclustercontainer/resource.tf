@@ -0,0 +1,21 @@
+resource "pgrmongodb_atlasclustercontainer" "aws" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  cloud_provider = "AWS"
+  atlas_cidr_block = "10.8.0.0/21"
+  region = "US_EAST_1"
+}
+
+resource "pgrmongodb_atlasclustercontainer" "azure" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  cloud_provider = "AZURE"
+  atlas_cidr_block = "10.16.0.0/21"
+  region = "US_EAST_2"
+}
+
+# GCP containers are global, regions limits the regions they serve
+resource "pgrmongodb_atlasclustercontainer" "gcp" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  cloud_provider = "GCP"
+  atlas_cidr_block = "10.32.0.0/18"
+  regions = ["CENTRAL_US", "EASTERN_US"]
+}
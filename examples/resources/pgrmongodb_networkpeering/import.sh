@@ -0,0 +1,2 @@
+# Network peering connections can be imported with project_id,peering_id
+terraform import pgrmongodb_networkpeering.aws 5f1a0f0b4e2c3d0a1b2c3d4e,5f1a0f0b4e2c3d0a1b2c3d50
@@ -0,0 +1,32 @@
+data "pgrmongodb_atlasclustercontainer" "aws" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  cloud_provider = "AWS"
+}
+
+resource "pgrmongodb_networkpeering" "aws" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  container_id = data.pgrmongodb_atlasclustercontainer.aws.ids["AWS:US_EAST_1"]
+  cloud_provider = "AWS"
+  accepter_region_name = "us-east-1"
+  aws_account_id = "<AWS ACCOUNT ID>"
+  route_table_cidr_block = "10.100.0.0/16"
+  vpc_id = "<AWS VPC ID>"
+}
+
+# accept the request Atlas sends, after which the peering becomes AVAILABLE
+resource "aws_vpc_peering_connection_accepter" "atlas" {
+  vpc_peering_connection_id = pgrmongodb_networkpeering.aws.connection_id
+  auto_accept = true
+}
+
+resource "pgrmongodb_networkpeering" "gcp" {
+  project_id = "<MONGODB ATLAS PROJECT/GROUP ID>"
+  container_id = "<ATLAS GCP CONTAINER ID>"
+  cloud_provider = "GCP"
+  gcp_project_id = "<GCP PROJECT ID>"
+  network_name = "<GCP VPC NETWORK NAME>"
+
+  timeouts {
+    create = "45m"
+  }
+}
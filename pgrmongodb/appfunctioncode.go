@@ -0,0 +1,161 @@
+package pgrmongodb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/evanw/esbuild/pkg/api"
+	"github.com/tdewolff/parse/v2"
+	"github.com/tdewolff/parse/v2/js"
+)
+
+// transpiles function source to the JavaScript deployed to App Services. javascript is returned unchanged
+func compileAppFunctionCode(functionCode string, language string) (string, *parse.Error) {
+	if language != "typescript" {
+		return functionCode, nil
+	}
+
+	result := api.Transform(functionCode, api.TransformOptions{
+		Loader:     api.LoaderTS,
+		Target:     api.ES2020,
+		Sourcefile: "function_code.ts",
+		LogLevel:   api.LogLevelSilent,
+	})
+	if len(result.Errors) > 0 {
+		return "", esbuildMessageToParseError(result.Errors[0])
+	}
+	return string(result.Code), nil
+}
+
+// bundles an entrypoint file and its local relative imports into a single function source. bare module
+// imports (npm packages installed with pgrmongodb_appfunctiondependencies and node builtins) are left external
+func bundleAppFunctionEntrypoint(entrypoint string) (string, *parse.Error) {
+	absEntrypoint, err := filepath.Abs(entrypoint)
+	if err != nil {
+		return "", &parse.Error{Message: err.Error(), Line: 1, Column: 1}
+	}
+
+	result := api.Build(api.BuildOptions{
+		EntryPoints: []string{absEntrypoint},
+		// keeps the module path comments in the bundle (and therefore function_code_hash) independent of where terraform runs
+		AbsWorkingDir: filepath.Dir(absEntrypoint),
+		Bundle:        true,
+		Platform:      api.PlatformNode,
+		Target:        api.ES2020,
+		Write:         false,
+		LogLevel:      api.LogLevelSilent,
+		Plugins: []api.Plugin{{
+			Name: "pgrmongodb-external-packages",
+			Setup: func(build api.PluginBuild) {
+				build.OnResolve(api.OnResolveOptions{Filter: `^[^./]`}, func(args api.OnResolveArgs) (api.OnResolveResult, error) {
+					return api.OnResolveResult{Path: args.Path, External: true}, nil
+				})
+			},
+		}},
+	})
+	if len(result.Errors) > 0 {
+		parseErr := esbuildMessageToParseError(result.Errors[0])
+		if result.Errors[0].Location != nil {
+			parseErr.Message = fmt.Sprintf("%s: %s", result.Errors[0].Location.File, parseErr.Message)
+		}
+		return "", parseErr
+	}
+	if len(result.OutputFiles) != 1 {
+		return "", &parse.Error{Message: fmt.Sprintf("expected 1 bundled output file but got %d", len(result.OutputFiles)), Line: 1, Column: 1}
+	}
+	return string(result.OutputFiles[0].Contents), nil
+}
+
+// reads every .js file in dir as a function source keyed by function name (file name without extension)
+func readAppFunctionsDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	functions := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".js" {
+			continue
+		}
+		functionCode, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		functions[strings.TrimSuffix(entry.Name(), ".js")] = string(functionCode)
+	}
+	return functions, nil
+}
+
+func esbuildMessageToParseError(message api.Message) *parse.Error {
+	if message.Location == nil {
+		return &parse.Error{Message: message.Text, Line: 1, Column: 1}
+	}
+	// esbuild columns are 0-based
+	return &parse.Error{
+		Message: message.Text,
+		Line:    message.Location.Line,
+		Column:  message.Location.Column + 1,
+		Context: message.Location.LineText,
+	}
+}
+
+func hashAppFunctionCode(functionCode string) string {
+	hash := sha256.Sum256([]byte(functionCode))
+	return hex.EncodeToString(hash[:])
+}
+
+// parses App Services function source and reports whether it assigns exports (exports = ..., exports.x = ..., module.exports = ...)
+func parseAppFunctionCode(functionCode string) (bool, *parse.Error) {
+	ast, err := js.Parse(parse.NewInputString(functionCode), js.Options{})
+	if err != nil {
+		var parseErr *parse.Error
+		if errors.As(err, &parseErr) {
+			return false, parseErr
+		}
+		return false, &parse.Error{Message: err.Error(), Line: 1, Column: 1}
+	}
+
+	visitor := &exportsAssignmentVisitor{}
+	js.Walk(visitor, ast)
+	return visitor.found, nil
+}
+
+type exportsAssignmentVisitor struct {
+	found bool
+}
+
+func (v *exportsAssignmentVisitor) Enter(n js.INode) js.IVisitor {
+	if v.found {
+		return nil
+	}
+	if expr, ok := n.(*js.BinaryExpr); ok && expr.Op == js.EqToken && isExportsTarget(expr.X) {
+		v.found = true
+		return nil
+	}
+	return v
+}
+
+func (v *exportsAssignmentVisitor) Exit(_ js.INode) {}
+
+func isExportsTarget(expr js.IExpr) bool {
+	switch target := expr.(type) {
+	case *js.Var:
+		return string(target.Data) == "exports"
+	case *js.DotExpr:
+		if isExportsTarget(target.X) {
+			return true
+		}
+		if v, ok := target.X.(*js.Var); ok && string(v.Data) == "module" {
+			return string(target.Y.Data) == "exports"
+		}
+	case *js.IndexExpr:
+		return isExportsTarget(target.X)
+	}
+	return false
+}
@@ -0,0 +1,334 @@
+package pgrmongodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	ephemeralschema "github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// descriptions of the expect block, shared by every schema that executes a function
+const (
+	appFunctionExpectDescription            = "Checks run against the function result after execution. Extended JSON numbers such as {\"$numberInt\": \"42\"} are compared as plain numbers. A failed check fails with the actual value."
+	appFunctionExpectPathDescription        = "JSONPath of the value to check, for example $.migrated or $.items[0]['name']. $ is the whole result."
+	appFunctionExpectEqualsDescription      = "JSON encoded value the result must equal, for example jsonencode(\"done\")."
+	appFunctionExpectGreaterThanDescription = "Number the result must be greater than."
+	appFunctionExpectLessThanDescription    = "Number the result must be less than."
+)
+
+func appFunctionExpectPathValidators() []validator.String {
+	return []validator.String{
+		jsonPathValidator{},
+	}
+}
+
+func appFunctionExpectEqualsValidators() []validator.String {
+	return []validator.String{
+		jsonStringValidator{},
+		stringvalidator.AtLeastOneOf(
+			path.MatchRelative().AtParent().AtName("greater_than"),
+			path.MatchRelative().AtParent().AtName("less_than"),
+		),
+	}
+}
+
+// one expect block, a check against the value at Path in the function result
+type appFunctionExpectModel struct {
+	Path        types.String  `tfsdk:"path"`
+	Equals      types.String  `tfsdk:"equals"`
+	GreaterThan types.Float64 `tfsdk:"greater_than"`
+	LessThan    types.Float64 `tfsdk:"less_than"`
+}
+
+// the expect block for data source schemas
+func appFunctionExpectDataSourceBlock() datasourceschema.ListNestedBlock {
+	return datasourceschema.ListNestedBlock{
+		Description: appFunctionExpectDescription,
+		NestedObject: datasourceschema.NestedBlockObject{
+			Attributes: map[string]datasourceschema.Attribute{
+				"path": datasourceschema.StringAttribute{
+					Description: appFunctionExpectPathDescription,
+					Required:    true,
+					Validators:  appFunctionExpectPathValidators(),
+				},
+				"equals": datasourceschema.StringAttribute{
+					Description: appFunctionExpectEqualsDescription,
+					Optional:    true,
+					Validators:  appFunctionExpectEqualsValidators(),
+				},
+				"greater_than": datasourceschema.Float64Attribute{
+					Description: appFunctionExpectGreaterThanDescription,
+					Optional:    true,
+				},
+				"less_than": datasourceschema.Float64Attribute{
+					Description: appFunctionExpectLessThanDescription,
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+// the expect block for resource schemas
+func appFunctionExpectResourceBlock() resourceschema.ListNestedBlock {
+	return resourceschema.ListNestedBlock{
+		Description: appFunctionExpectDescription,
+		NestedObject: resourceschema.NestedBlockObject{
+			Attributes: map[string]resourceschema.Attribute{
+				"path": resourceschema.StringAttribute{
+					Description: appFunctionExpectPathDescription,
+					Required:    true,
+					Validators:  appFunctionExpectPathValidators(),
+				},
+				"equals": resourceschema.StringAttribute{
+					Description: appFunctionExpectEqualsDescription,
+					Optional:    true,
+					Validators:  appFunctionExpectEqualsValidators(),
+				},
+				"greater_than": resourceschema.Float64Attribute{
+					Description: appFunctionExpectGreaterThanDescription,
+					Optional:    true,
+				},
+				"less_than": resourceschema.Float64Attribute{
+					Description: appFunctionExpectLessThanDescription,
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+// the expect block for ephemeral resource schemas
+func appFunctionExpectEphemeralBlock() ephemeralschema.ListNestedBlock {
+	return ephemeralschema.ListNestedBlock{
+		Description: "Checks run against the function result after execution. Extended JSON numbers such as {\"$numberInt\": \"42\"} are compared as plain numbers. A failed check does not show the actual value since the result is sensitive.",
+		NestedObject: ephemeralschema.NestedBlockObject{
+			Attributes: map[string]ephemeralschema.Attribute{
+				"path": ephemeralschema.StringAttribute{
+					Description: appFunctionExpectPathDescription,
+					Required:    true,
+					Validators:  appFunctionExpectPathValidators(),
+				},
+				"equals": ephemeralschema.StringAttribute{
+					Description: appFunctionExpectEqualsDescription,
+					Optional:    true,
+					Validators:  appFunctionExpectEqualsValidators(),
+				},
+				"greater_than": ephemeralschema.Float64Attribute{
+					Description: appFunctionExpectGreaterThanDescription,
+					Optional:    true,
+				},
+				"less_than": ephemeralschema.Float64Attribute{
+					Description: appFunctionExpectLessThanDescription,
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+// runs the expect blocks against resultJSON. Each failed check is reported with the actual value, unless
+// sensitive is set and the result must not show up in diagnostics
+func checkAppFunctionExpectations(functionName string, resultJSON string, expectations []appFunctionExpectModel, sensitive bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(expectations) == 0 {
+		return diags
+	}
+	shown := func(value string) string {
+		if sensitive {
+			return "(sensitive value)"
+		}
+		return value
+	}
+
+	var result interface{}
+	if err := json.Unmarshal([]byte(resultJSON), &result); err != nil {
+		diags.AddError(
+			"MongoDB Atlas App Services Function Result Check Failed",
+			fmt.Sprintf("result of app function %s is not valid JSON: %s", functionName, err),
+		)
+		return diags
+	}
+	result = normalizeEJSON(result)
+
+	for i, expect := range expectations {
+		attributePath := path.Root("expect").AtListIndex(i)
+		jsonPath := expect.Path.ValueString()
+		actual, found, err := lookupJSONPath(result, jsonPath)
+		if err != nil {
+			diags.AddAttributeError(attributePath.AtName("path"), "Invalid JSONPath", err.Error())
+			continue
+		}
+		if !found {
+			diags.AddAttributeError(
+				attributePath,
+				"MongoDB Atlas App Services Function Result Check Failed",
+				fmt.Sprintf("result of app function %s has no value at %s. Result: %s", functionName, jsonPath, shown(resultJSON)),
+			)
+			continue
+		}
+		actualJSON, _ := json.Marshal(actual)
+
+		if !expect.Equals.IsNull() {
+			var expected interface{}
+			if err := json.Unmarshal([]byte(expect.Equals.ValueString()), &expected); err != nil {
+				diags.AddAttributeError(attributePath.AtName("equals"), "Invalid JSON Value", err.Error())
+				continue
+			}
+			if !reflect.DeepEqual(actual, normalizeEJSON(expected)) {
+				diags.AddAttributeError(
+					attributePath,
+					"MongoDB Atlas App Services Function Result Check Failed",
+					fmt.Sprintf("result of app function %s at %s is %s, expected it to equal %s", functionName, jsonPath, shown(string(actualJSON)), expect.Equals.ValueString()),
+				)
+			}
+		}
+
+		if expect.GreaterThan.IsNull() && expect.LessThan.IsNull() {
+			continue
+		}
+		number, ok := actual.(float64)
+		if !ok {
+			diags.AddAttributeError(
+				attributePath,
+				"MongoDB Atlas App Services Function Result Check Failed",
+				fmt.Sprintf("result of app function %s at %s is %s, expected a number", functionName, jsonPath, shown(string(actualJSON))),
+			)
+			continue
+		}
+		if !expect.GreaterThan.IsNull() && !(number > expect.GreaterThan.ValueFloat64()) {
+			diags.AddAttributeError(
+				attributePath,
+				"MongoDB Atlas App Services Function Result Check Failed",
+				fmt.Sprintf("result of app function %s at %s is %s, expected it to be greater than %s", functionName, jsonPath, shown(string(actualJSON)), strconv.FormatFloat(expect.GreaterThan.ValueFloat64(), 'f', -1, 64)),
+			)
+		}
+		if !expect.LessThan.IsNull() && !(number < expect.LessThan.ValueFloat64()) {
+			diags.AddAttributeError(
+				attributePath,
+				"MongoDB Atlas App Services Function Result Check Failed",
+				fmt.Sprintf("result of app function %s at %s is %s, expected it to be less than %s", functionName, jsonPath, shown(string(actualJSON)), strconv.FormatFloat(expect.LessThan.ValueFloat64(), 'f', -1, 64)),
+			)
+		}
+	}
+	return diags
+}
+
+// converts canonical Extended JSON wrappers such as {"$numberInt": "42"} to plain JSON values, so results
+// can be compared with values written in HCL
+func normalizeEJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeEJSON(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		if len(v) == 1 {
+			for key, wrapped := range v {
+				switch key {
+				case "$numberInt", "$numberLong", "$numberDouble", "$numberDecimal":
+					if s, ok := wrapped.(string); ok {
+						if number, err := strconv.ParseFloat(s, 64); err == nil {
+							return number
+						}
+					}
+				case "$oid", "$symbol":
+					if s, ok := wrapped.(string); ok {
+						return s
+					}
+				}
+			}
+		}
+		for key := range v {
+			v[key] = normalizeEJSON(v[key])
+		}
+		return v
+	}
+	return value
+}
+
+// splits a JSONPath into object keys and array indexes. The supported subset is a leading $ followed by
+// .name, ['name'] and [index] segments, for example $.stats.migrated or $.items[0]['display name']
+func parseJSONPath(jsonPath string) ([]interface{}, error) {
+	rest := strings.TrimSpace(jsonPath)
+	if !strings.HasPrefix(rest, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", jsonPath)
+	}
+	rest = rest[1:]
+
+	segments := []interface{}{}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			name := rest[1 : end+1]
+			if name == "" {
+				return nil, fmt.Errorf("JSONPath %q has an empty name after '.'", jsonPath)
+			}
+			segments = append(segments, name)
+			rest = rest[end+1:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("JSONPath %q has an unterminated '['", jsonPath)
+			}
+			inner := rest[1:end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				segments = append(segments, inner[1:len(inner)-1])
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("JSONPath %q has an unsupported segment [%s], only quoted names and array indexes are supported", jsonPath, inner)
+				}
+				segments = append(segments, index)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("JSONPath %q has an unexpected character %q", jsonPath, rest[0])
+		}
+	}
+	return segments, nil
+}
+
+// returns the value at jsonPath and whether it exists
+func lookupJSONPath(value interface{}, jsonPath string) (interface{}, bool, error) {
+	segments, err := parseJSONPath(jsonPath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	current := value
+	for _, segment := range segments {
+		switch s := segment.(type) {
+		case string:
+			object, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false, nil
+			}
+			if current, ok = object[s]; !ok {
+				return nil, false, nil
+			}
+		case int:
+			array, ok := current.([]interface{})
+			if !ok || s >= len(array) {
+				return nil, false, nil
+			}
+			current = array[s]
+		}
+	}
+	return current, true, nil
+}
@@ -0,0 +1,151 @@
+package pgrmongodb
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		jsonPath string
+		want     []interface{}
+		wantErr  bool
+	}{
+		{jsonPath: "$", want: []interface{}{}},
+		{jsonPath: " $.migrated ", want: []interface{}{"migrated"}},
+		{jsonPath: "$.stats.migrated", want: []interface{}{"stats", "migrated"}},
+		{jsonPath: "$.items[0]['display name']", want: []interface{}{"items", 0, "display name"}},
+		{jsonPath: `$["a.b"][12]`, want: []interface{}{"a.b", 12}},
+		{jsonPath: "migrated", wantErr: true},
+		{jsonPath: "$.", wantErr: true},
+		{jsonPath: "$..items", wantErr: true},
+		{jsonPath: "$.items[0", wantErr: true},
+		{jsonPath: "$.items[-1]", wantErr: true},
+		{jsonPath: "$.items[*]", wantErr: true},
+		{jsonPath: "$items", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.jsonPath, func(t *testing.T) {
+			got, err := parseJSONPath(tt.jsonPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	var result interface{}
+	if err := json.Unmarshal([]byte(`{"migrated": 3, "items": [{"name": "a"}, {"name": null}], "nested": {"ok": true}}`), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		jsonPath  string
+		want      interface{}
+		wantFound bool
+		wantErr   bool
+	}{
+		{jsonPath: "$.migrated", want: float64(3), wantFound: true},
+		{jsonPath: "$.items[0].name", want: "a", wantFound: true},
+		{jsonPath: "$.items[1]['name']", want: nil, wantFound: true},
+		{jsonPath: "$.nested.ok", want: true, wantFound: true},
+		{jsonPath: "$.missing"},
+		{jsonPath: "$.items[2]"},
+		{jsonPath: "$.migrated.value"},
+		{jsonPath: "$.nested[0]"},
+		{jsonPath: "migrated", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.jsonPath, func(t *testing.T) {
+			got, found, err := lookupJSONPath(result, tt.jsonPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if found != tt.wantFound {
+				t.Fatalf("found is %t, want %t", found, tt.wantFound)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeEJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "int", value: `{"$numberInt": "42"}`, want: `42`},
+		{name: "long", value: `{"$numberLong": "9007199254740991"}`, want: `9007199254740991`},
+		{name: "double", value: `{"$numberDouble": "1.5"}`, want: `1.5`},
+		{name: "decimal", value: `{"$numberDecimal": "0.1"}`, want: `0.1`},
+		{name: "oid", value: `{"$oid": "000000000000000000000001"}`, want: `"000000000000000000000001"`},
+		{name: "nested", value: `{"count": {"$numberInt": "2"}, "items": [{"$numberLong": "7"}, "x"]}`, want: `{"count": 2, "items": [7, "x"]}`},
+		{name: "not a number", value: `{"$numberInt": "NaN?"}`, want: `{"$numberInt": "NaN?"}`},
+		{name: "wrapper with other keys", value: `{"$numberInt": "1", "other": true}`, want: `{"$numberInt": "1", "other": true}`},
+		{name: "plain", value: `{"migrated": 3}`, want: `{"migrated": 3}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value, want interface{}
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if got := normalizeEJSON(value); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestCheckAppFunctionExpectations(t *testing.T) {
+	resultJSON := `{"migrated": {"$numberInt": "3"}, "status": "done"}`
+	// unset fields of appFunctionExpectModel are null
+	tests := []struct {
+		name    string
+		expect  appFunctionExpectModel
+		wantErr bool
+	}{
+		{name: "equals string", expect: appFunctionExpectModel{Path: types.StringValue("$.status"), Equals: types.StringValue(`"done"`)}},
+		{name: "equals ejson number", expect: appFunctionExpectModel{Path: types.StringValue("$.migrated"), Equals: types.StringValue(`3`)}},
+		{name: "not equal", expect: appFunctionExpectModel{Path: types.StringValue("$.status"), Equals: types.StringValue(`"failed"`)}, wantErr: true},
+		{name: "greater than", expect: appFunctionExpectModel{Path: types.StringValue("$.migrated"), GreaterThan: types.Float64Value(0)}},
+		{name: "not less than", expect: appFunctionExpectModel{Path: types.StringValue("$.migrated"), LessThan: types.Float64Value(3)}, wantErr: true},
+		{name: "not a number", expect: appFunctionExpectModel{Path: types.StringValue("$.status"), GreaterThan: types.Float64Value(0)}, wantErr: true},
+		{name: "missing", expect: appFunctionExpectModel{Path: types.StringValue("$.missing"), Equals: types.StringValue(`1`)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := checkAppFunctionExpectations("myfunction", resultJSON, []appFunctionExpectModel{tt.expect}, false)
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("HasError is %t, want %t: %v", diags.HasError(), tt.wantErr, diags)
+			}
+		})
+	}
+}
+
+func TestCheckAppFunctionExpectationsSensitive(t *testing.T) {
+	expect := appFunctionExpectModel{Path: types.StringValue("$.key"), Equals: types.StringValue(`"other"`)}
+	diags := checkAppFunctionExpectations("myfunction", `{"key": "secret"}`, []appFunctionExpectModel{expect}, true)
+	if !diags.HasError() {
+		t.Fatal("expected a failed check")
+	}
+	for _, d := range diags {
+		if strings.Contains(d.Detail(), "secret") {
+			t.Errorf("diagnostic shows the sensitive result: %s", d.Detail())
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package pgrmongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	ephemeralschema "github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// descriptions of the retry attributes, shared by every schema that executes a function
+const (
+	appFunctionMaxAttemptsDescription = "Maximum number of times to execute the function when a retryable failure occurs. Defaults to 1."
+	appFunctionRetryOnDescription     = "Failures that are retried, any of timeout, 5xx and function_error. Defaults to timeout and 5xx. A timed out run is only retried when the app logs show it finished with an error. A run that is still running, or whose outcome is unknown, is never retried and fails with an error saying it may still be running."
+	appFunctionRetryDelayDescription  = "Time to wait between attempts, for example 10s or 1m. Defaults to 5s."
+)
+
+// failures that can be listed in retry_on
+var appFunctionRetryReasons = []string{"timeout", "5xx", "function_error"}
+
+func appFunctionMaxAttemptsValidators() []validator.Int64 {
+	return []validator.Int64{
+		int64validator.AtLeast(1),
+	}
+}
+
+func appFunctionRetryOnValidators() []validator.Set {
+	return []validator.Set{
+		setvalidator.ValueStringsAre(stringvalidator.OneOf(appFunctionRetryReasons...)),
+	}
+}
+
+func appFunctionRetryDelayValidators() []validator.String {
+	return []validator.String{
+		durationStringValidator{},
+	}
+}
+
+// max_attempts, retry_on and retry_delay for data source schemas
+func appFunctionRetryDataSourceAttributes() map[string]datasourceschema.Attribute {
+	return map[string]datasourceschema.Attribute{
+		"max_attempts": datasourceschema.Int64Attribute{
+			Description: appFunctionMaxAttemptsDescription,
+			Optional:    true,
+			Validators:  appFunctionMaxAttemptsValidators(),
+		},
+		"retry_on": datasourceschema.SetAttribute{
+			Description: appFunctionRetryOnDescription,
+			Optional:    true,
+			ElementType: types.StringType,
+			Validators:  appFunctionRetryOnValidators(),
+		},
+		"retry_delay": datasourceschema.StringAttribute{
+			Description: appFunctionRetryDelayDescription,
+			Optional:    true,
+			Validators:  appFunctionRetryDelayValidators(),
+		},
+	}
+}
+
+// max_attempts, retry_on and retry_delay for resource schemas
+func appFunctionRetryResourceAttributes() map[string]resourceschema.Attribute {
+	return map[string]resourceschema.Attribute{
+		"max_attempts": resourceschema.Int64Attribute{
+			Description: appFunctionMaxAttemptsDescription,
+			Optional:    true,
+			Validators:  appFunctionMaxAttemptsValidators(),
+		},
+		"retry_on": resourceschema.SetAttribute{
+			Description: appFunctionRetryOnDescription,
+			Optional:    true,
+			ElementType: types.StringType,
+			Validators:  appFunctionRetryOnValidators(),
+		},
+		"retry_delay": resourceschema.StringAttribute{
+			Description: appFunctionRetryDelayDescription,
+			Optional:    true,
+			Validators:  appFunctionRetryDelayValidators(),
+		},
+	}
+}
+
+// max_attempts, retry_on and retry_delay for ephemeral resource schemas
+func appFunctionRetryEphemeralAttributes() map[string]ephemeralschema.Attribute {
+	return map[string]ephemeralschema.Attribute{
+		"max_attempts": ephemeralschema.Int64Attribute{
+			Description: appFunctionMaxAttemptsDescription,
+			Optional:    true,
+			Validators:  appFunctionMaxAttemptsValidators(),
+		},
+		"retry_on": ephemeralschema.SetAttribute{
+			Description: appFunctionRetryOnDescription,
+			Optional:    true,
+			ElementType: types.StringType,
+			Validators:  appFunctionRetryOnValidators(),
+		},
+		"retry_delay": ephemeralschema.StringAttribute{
+			Description: appFunctionRetryDelayDescription,
+			Optional:    true,
+			Validators:  appFunctionRetryDelayValidators(),
+		},
+	}
+}
+
+// builds the retry policy from max_attempts, retry_on and retry_delay
+func appFunctionRetryPolicyFromConfig(ctx context.Context, maxAttempts types.Int64, retryOn types.Set, retryDelay types.String) (appFunctionRetryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	policy := appFunctionRetryPolicy{
+		MaxAttempts: 1,
+		RetryOn:     []string{"timeout", "5xx"},
+		RetryDelay:  5 * time.Second,
+	}
+
+	if !maxAttempts.IsNull() {
+		policy.MaxAttempts = maxAttempts.ValueInt64()
+	}
+	if !retryOn.IsNull() {
+		policy.RetryOn = make([]string, 0, len(retryOn.Elements()))
+		diags.Append(retryOn.ElementsAs(ctx, &policy.RetryOn, false)...)
+	}
+	if !retryDelay.IsNull() {
+		delay, err := time.ParseDuration(retryDelay.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("retry_delay"), "Invalid Duration Value", err.Error())
+			return policy, diags
+		}
+		policy.RetryDelay = delay
+	}
+	return policy, diags
+}
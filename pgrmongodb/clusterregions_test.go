@@ -0,0 +1,52 @@
+package pgrmongodb
+
+import "testing"
+
+func TestNormalizeAtlasRegion(t *testing.T) {
+	tests := []struct {
+		providerName string
+		region       string
+		want         string
+	}{
+		{providerName: "AWS", region: "us-east-1", want: "US_EAST_1"},
+		{providerName: "AWS", region: "US_EAST_1", want: "US_EAST_1"},
+		{providerName: "AZURE", region: "eastus2", want: "US_EAST_2"},
+		{providerName: "AZURE", region: "US_EAST_2", want: "US_EAST_2"},
+		{providerName: "GCP", region: "us-east4", want: "US_EAST_4"},
+		{providerName: "GCP", region: "us-central1", want: "CENTRAL_US"},
+		{providerName: "GCP", region: "europe-west1", want: "WESTERN_EUROPE"},
+		{providerName: "GCP", region: " CENTRAL_US ", want: "CENTRAL_US"},
+	}
+	for _, tt := range tests {
+		if got := normalizeAtlasRegion(tt.providerName, tt.region); got != tt.want {
+			t.Errorf("normalizeAtlasRegion(%q, %q) is %q, want %q", tt.providerName, tt.region, got, tt.want)
+		}
+	}
+}
+
+func TestClusterContainerServesRegion(t *testing.T) {
+	global := clusterContainer{ProviderName: "GCP"}
+	regional := clusterContainer{ProviderName: "GCP", Regions: []string{"CENTRAL_US", "US_EAST_4"}}
+	tests := []struct {
+		name      string
+		container clusterContainer
+		region    string
+		want      bool
+	}{
+		{name: "global", container: global, region: "GLOBAL", want: true},
+		{name: "global lower case", container: global, region: "global", want: true},
+		{name: "global with a region", container: global, region: "CENTRAL_US", want: false},
+		{name: "global with a typo", container: global, region: "CENTRL_US", want: false},
+		{name: "atlas spelling", container: regional, region: "US_EAST_4", want: true},
+		{name: "gcp spelling", container: regional, region: "us-central1", want: true},
+		{name: "other region", container: regional, region: "us-west1", want: false},
+		{name: "regional with global", container: regional, region: "GLOBAL", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.container.servesRegion(tt.region); got != tt.want {
+				t.Errorf("servesRegion(%q) is %t, want %t", tt.region, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package pgrmongodb
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &appFunctionDataSource{}
+)
+
+func NewAppFunctionDataSource() datasource.DataSource {
+	return &appFunctionDataSource{}
+}
+
+type appFunctionDataSource struct {
+	bearer_token string
+}
+
+type appFunctionDataSourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	ProjectID        types.String `tfsdk:"project_id"`
+	AppServicesAppID types.String `tfsdk:"appservices_app_id"`
+	FunctionID       types.String `tfsdk:"function_id"`
+	FunctionName     types.String `tfsdk:"function_name"`
+	FunctionCode     types.String `tfsdk:"function_code"`
+	Private          types.Bool   `tfsdk:"private"`
+	RunAsSystem      types.Bool   `tfsdk:"run_as_system"`
+	LastModified     types.String `tfsdk:"last_modified"`
+}
+
+func (r *appFunctionDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_appfunction"
+}
+
+func (r *appFunctionDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data lookup for a MongoDB Atlas App Services Function",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"project_id": schema.StringAttribute{
+				Description: "MongoDB Atlas project identifier. Sometime referred to as group id.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal project_id",
+					),
+				},
+			},
+			"appservices_app_id": schema.StringAttribute{
+				Description: "MongoDB Atlas App Services app id to read the function from.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal appservices_app_id",
+					),
+				},
+			},
+			"function_id": schema.StringAttribute{
+				Description: "Identifier of the function to read. Exactly one of function_id or function_name must be set.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("function_name")),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal function_id",
+					),
+				},
+			},
+			"function_name": schema.StringAttribute{
+				Description: "Name of the function to read. Exactly one of function_id or function_name must be set.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"function_code": schema.StringAttribute{
+				Description: "Source code of the function.",
+				Computed:    true,
+			},
+			"private": schema.BoolAttribute{
+				Description: "Whether the function can only be called from other functions and rules.",
+				Computed:    true,
+			},
+			"run_as_system": schema.BoolAttribute{
+				Description: "Whether the function runs as the system user.",
+				Computed:    true,
+			},
+			"last_modified": schema.StringAttribute{
+				Description: "Timestamp for the last time the function was modified.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *appFunctionDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.bearer_token = req.ProviderData.(providerData).bearer_token
+}
+
+func (r *appFunctionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state appFunctionDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectID := state.ProjectID.ValueString()
+	appServicesAppID := state.AppServicesAppID.ValueString()
+
+	tflog.Info(ctx, "reading mongodb atlas app services function")
+	summary, err := getAppServicesFunctionSummary(r.bearer_token, projectID, appServicesAppID, state.FunctionID.ValueString(), state.FunctionName.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Get MongoDB Atlas App Services Function",
+			err.Error(),
+		)
+		return
+	}
+	functionID, ok := summary["_id"].(string)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unable to Get MongoDB Atlas App Services Function",
+			"app services returned a function without an _id",
+		)
+		return
+	}
+
+	function, err := getAppServicesFunctionDetailsByID(r.bearer_token, projectID, appServicesAppID, functionID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Get MongoDB Atlas App Services Function",
+			err.Error(),
+		)
+		return
+	}
+	functionName, ok := function["name"].(string)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unable to Get MongoDB Atlas App Services Function",
+			"app services returned function "+functionID+" without a name",
+		)
+		return
+	}
+	functionCode, _ := function["source"].(string)
+
+	state.ID = types.StringValue(functionID)
+	state.FunctionID = types.StringValue(functionID)
+	state.FunctionName = types.StringValue(functionName)
+	state.FunctionCode = types.StringValue(functionCode)
+	state.Private = types.BoolValue(function["private"] == true)
+	state.RunAsSystem = types.BoolValue(function["run_as_system"] == true)
+	state.LastModified = types.StringValue(appServicesTimestamp(summary["last_modified"]))
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// App Services reports last_modified as seconds since epoch
+func appServicesTimestamp(value interface{}) string {
+	seconds, ok := value.(float64)
+	if !ok {
+		return ""
+	}
+	return time.Unix(int64(seconds), 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,30 @@
+package pgrmongodb
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// note this checks for a function named myfunction in the app
+func TestAccPGRMongoDBAppFunctionDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunction" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunction.test", "id"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunction.test", "function_id"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunction.test", "function_code"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunction.test", "last_modified"),
+				),
+			},
+		},
+	})
+}
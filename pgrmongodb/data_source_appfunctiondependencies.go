@@ -0,0 +1,220 @@
+package pgrmongodb
+
+import (
+	"context"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &appFunctionDependenciesDataSource{}
+)
+
+func NewAppFunctionDependenciesDataSource() datasource.DataSource {
+	return &appFunctionDependenciesDataSource{}
+}
+
+type appFunctionDependenciesDataSource struct {
+	bearer_token string
+}
+
+type appFunctionDependenciesDataSourceModel struct {
+	ID               types.String                                  `tfsdk:"id"`
+	ProjectID        types.String                                  `tfsdk:"project_id"`
+	AppServicesAppID types.String                                  `tfsdk:"appservices_app_id"`
+	Status           types.String                                  `tfsdk:"status"`
+	StatusMessage    types.String                                  `tfsdk:"status_message"`
+	Dependencies     []appFunctionDependenciesDataSourceDependency `tfsdk:"dependencies"`
+	Packages         []appFunctionDependenciesDataSourcePackage    `tfsdk:"packages"`
+}
+
+type appFunctionDependenciesDataSourceDependency struct {
+	Name    types.String `tfsdk:"name"`
+	Version types.String `tfsdk:"version"`
+}
+
+type appFunctionDependenciesDataSourcePackage struct {
+	Name       types.String `tfsdk:"name"`
+	Version    types.String `tfsdk:"version"`
+	Direct     types.Bool   `tfsdk:"direct"`
+	RequiredBy types.List   `tfsdk:"required_by"`
+}
+
+func (r *appFunctionDependenciesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_appfunctiondependencies"
+}
+
+func (r *appFunctionDependenciesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data lookup for the build status and resolved npm packages of MongoDB Atlas App Services Function Dependencies",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"project_id": schema.StringAttribute{
+				Description: "MongoDB Atlas project identifier. Sometime referred to as group id.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal project_id",
+					),
+				},
+			},
+			"appservices_app_id": schema.StringAttribute{
+				Description: "MongoDB Atlas App Services app id to read dependencies from.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal appservices_app_id",
+					),
+				},
+			},
+			"status": schema.StringAttribute{
+				Description: "Status of the last dependency build, for example created, successful or failed.",
+				Computed:    true,
+			},
+			"status_message": schema.StringAttribute{
+				Description: "Message of the last dependency build, holding the reason when the build failed.",
+				Computed:    true,
+			},
+			"dependencies": schema.ListNestedAttribute{
+				Description: "Top-level npm packages installed in the app, sorted by name.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "npm package name.",
+							Computed:    true,
+						},
+						"version": schema.StringAttribute{
+							Description: "Installed version.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"packages": schema.ListNestedAttribute{
+				Description: "Every resolved npm package including transitive dependencies, sorted by name and semver precedence. Intended for license and vulnerability scanning. Null with a warning when the API response does not include the resolved dependency tree.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "npm package name.",
+							Computed:    true,
+						},
+						"version": schema.StringAttribute{
+							Description: "Resolved version.",
+							Computed:    true,
+						},
+						"direct": schema.BoolAttribute{
+							Description: "Whether the package is a top-level dependency.",
+							Computed:    true,
+						},
+						"required_by": schema.ListAttribute{
+							Description: "Names of the packages that depend on this package, sorted by name.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *appFunctionDependenciesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.bearer_token = req.ProviderData.(providerData).bearer_token
+}
+
+func (r *appFunctionDependenciesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state appFunctionDependenciesDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectID := state.ProjectID.ValueString()
+	appServicesAppID := state.AppServicesAppID.ValueString()
+
+	tflog.Info(ctx, "reading mongodb atlas app services function dependencies")
+	status, statusMessage, err := getAppFunctionDependenciesStatus(r.bearer_token, projectID, appServicesAppID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading App Services Function Dependencies",
+			"Could not read MongoDB Atlas App Services Function Dependencies status. Received error: "+err.Error(),
+		)
+		return
+	}
+	dependencies, packages, err := getAppFunctionDependencyTree(r.bearer_token, projectID, appServicesAppID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading App Services Function Dependencies",
+			"Could not read MongoDB Atlas App Services Function Dependencies. Received error: "+err.Error(),
+		)
+		return
+	}
+
+	sort.Slice(dependencies, func(i, j int) bool {
+		return dependencies[i].Name < dependencies[j].Name
+	})
+	state.Dependencies = make([]appFunctionDependenciesDataSourceDependency, 0, len(dependencies))
+	direct := make(map[string]bool, len(dependencies))
+	for _, v := range dependencies {
+		state.Dependencies = append(state.Dependencies, appFunctionDependenciesDataSourceDependency{
+			Name:    types.StringValue(v.Name),
+			Version: types.StringValue(v.Version),
+		})
+		direct[v.Name+"@"+v.Version] = true
+	}
+
+	if packages == nil {
+		// a short list of only the top-level packages would hide transitive packages from scanning
+		resp.Diagnostics.AddWarning(
+			"Resolved App Services Function Dependencies Unavailable",
+			"The MongoDB Atlas App Services dependencies response does not include the resolved dependency tree, so packages is null.",
+		)
+		state.Packages = nil
+	} else {
+		state.Packages = make([]appFunctionDependenciesDataSourcePackage, 0, len(packages))
+	}
+	for _, v := range packages {
+		requiredBy, listDiags := types.ListValueFrom(ctx, types.StringType, v.RequiredBy)
+		resp.Diagnostics.Append(listDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.Packages = append(state.Packages, appFunctionDependenciesDataSourcePackage{
+			Name:       types.StringValue(v.Name),
+			Version:    types.StringValue(v.Version),
+			Direct:     types.BoolValue(direct[v.Name+"@"+v.Version]),
+			RequiredBy: requiredBy,
+		})
+	}
+
+	state.ID = types.StringValue(appFunctionDependenciesID(projectID, appServicesAppID))
+	state.Status = types.StringValue(status)
+	state.StatusMessage = types.StringValue(statusMessage)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,31 @@
+package pgrmongodb
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// note this checks for at least 1 installed dependency in the app
+func TestAccPGRMongoDBAppFunctionDependenciesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pgrmongodb_appfunctiondependencies.test", "id", "000000000000000000000000/000000000000000000000000"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctiondependencies.test", "status"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctiondependencies.test", "dependencies.0.name"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctiondependencies.test", "dependencies.0.version"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctiondependencies.test", "packages.0.name"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctiondependencies.test", "packages.0.version"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,235 @@
+package pgrmongodb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource                   = &appFunctionEvalDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &appFunctionEvalDataSource{}
+)
+
+func NewAppFunctionEvalDataSource() datasource.DataSource {
+	return &appFunctionEvalDataSource{}
+}
+
+type appFunctionEvalDataSource struct {
+	bearer_token string
+}
+
+type appFunctionEvalDataSourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	ProjectID        types.String `tfsdk:"project_id"`
+	AppServicesAppID types.String `tfsdk:"appservices_app_id"`
+	FunctionCode     types.String `tfsdk:"function_code"`
+	Language         types.String `tfsdk:"language"`
+	FunctionArgsJSON types.List   `tfsdk:"function_args_json"`
+	ExecutionTimeout types.Int64  `tfsdk:"execution_timeout"`
+	IgnoreErrors     types.Bool   `tfsdk:"ignore_function_errors"`
+	ResultJSON       types.String `tfsdk:"result_json"`
+	Logs             types.List   `tfsdk:"logs"`
+	Error            types.String `tfsdk:"error"`
+}
+
+func (r *appFunctionEvalDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_appfunction_eval"
+}
+
+func (r *appFunctionEvalDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Evaluates MongoDB Atlas App Services Function source without deploying a function. The source runs on every plan and refresh, so it suits read-only smoke checks. Use pgrmongodb_appfunction_invocation for migrations or other code that must run once",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"project_id": schema.StringAttribute{
+				Description: "MongoDB Atlas project identifier. Sometime referred to as group id.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal project_id",
+					),
+				},
+			},
+			"appservices_app_id": schema.StringAttribute{
+				Description: "MongoDB Atlas App Services app id to evaluate the source in.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal appservices_app_id",
+					),
+				},
+			},
+			"function_code": schema.StringAttribute{
+				Description: "Function source to evaluate. The value assigned to exports is called with function_args_json.",
+				Required:    true,
+			},
+			"language": schema.StringAttribute{
+				Description: "Language of function_code, either javascript or typescript. Defaults to javascript.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"javascript", "typescript"}...),
+				},
+			},
+			"function_args_json": schema.ListAttribute{
+				Description: "Ordered list of JSON encoded arguments to pass to the function, for example [jsonencode(42), jsonencode({ dryRun = true })].",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(jsonStringValidator{}),
+				},
+			},
+			"execution_timeout": schema.Int64Attribute{
+				Description: "Sets the timeout value for function evaluation (seconds).",
+				Optional:    true,
+			},
+			"ignore_function_errors": schema.BoolAttribute{
+				Description: "Do not fail when the function throws or returns an object with an error field. The error is still reported in the error attribute.",
+				Optional:    true,
+			},
+			"result_json": schema.StringAttribute{
+				Description: "JSON encoded value returned by the function.",
+				Computed:    true,
+			},
+			"logs": schema.ListAttribute{
+				Description: "Log lines written by the function during evaluation.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"error": schema.StringAttribute{
+				Description: "Error reported by the function, empty when the function succeeded.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *appFunctionEvalDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.bearer_token = req.ProviderData.(providerData).bearer_token
+}
+
+func (r *appFunctionEvalDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config appFunctionEvalDataSourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.FunctionCode.IsUnknown() || config.Language.IsUnknown() {
+		return
+	}
+
+	compiledCode, parseErr := compileAppFunctionCode(config.FunctionCode.ValueString(), config.Language.ValueString())
+	if parseErr != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("function_code"),
+			"Invalid App Services Function Code",
+			fmt.Sprintf("function_code contains a TypeScript compile error on line %d, column %d: %s\n\n%s", parseErr.Line, parseErr.Column, parseErr.Message, parseErr.Context),
+		)
+		return
+	}
+	hasExports, parseErr := parseAppFunctionCode(compiledCode)
+	if parseErr != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("function_code"),
+			"Invalid App Services Function Code",
+			fmt.Sprintf("function_code contains a JavaScript syntax error on line %d, column %d: %s\n\n%s", parseErr.Line, parseErr.Column, parseErr.Message, parseErr.Context),
+		)
+		return
+	}
+	if !hasExports {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("function_code"),
+			"App Services Function Code Does Not Assign exports",
+			"function_code never assigns exports. The value assigned to exports (for example exports = async function() { ... }) is what gets evaluated.",
+		)
+	}
+}
+
+func (r *appFunctionEvalDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state appFunctionEvalDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	functionArgsJSON := make([]string, 0, len(state.FunctionArgsJSON.Elements()))
+	diags = state.FunctionArgsJSON.ElementsAs(ctx, &functionArgsJSON, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	arguments, err := appFunctionArguments(nil, functionArgsJSON)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("function_args_json"),
+			"Invalid MongoDB Atlas App Services Function Arguments",
+			err.Error(),
+		)
+		return
+	}
+
+	functionCode, parseErr := compileAppFunctionCode(state.FunctionCode.ValueString(), state.Language.ValueString())
+	if parseErr != nil {
+		resp.Diagnostics.AddError(
+			"Error Compiling App Services Function",
+			"Could not compile function_code. Received error: "+parseErr.Error(),
+		)
+		return
+	}
+
+	tflog.Info(ctx, "evaluating mongodb atlas app services function source")
+	execution, err := executeAppServicesFunctionSource(r.bearer_token, state.ProjectID.ValueString(), state.AppServicesAppID.ValueString(), functionCode, arguments, state.ExecutionTimeout.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Evaluate MongoDB Atlas App Services Function Source",
+			err.Error(),
+		)
+		return
+	}
+	if execution.Error != "" && !state.IgnoreErrors.ValueBool() {
+		resp.Diagnostics.AddError(
+			"MongoDB Atlas App Services Function Reported an Error",
+			fmt.Sprintf("evaluated function source reported an error: %s\n\nLogs:\n%s", execution.Error, strings.Join(execution.Logs, "\n")),
+		)
+		return
+	}
+
+	state.ID = types.StringValue(hashAppFunctionCode(functionCode))
+	state.ResultJSON = types.StringValue(execution.ResultJSON)
+	state.Error = types.StringValue(execution.Error)
+	state.Logs, diags = types.ListValueFrom(ctx, types.StringType, execution.Logs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,34 @@
+package pgrmongodb
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccPGRMongoDBAppFunctionEval(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunction_eval" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_code = <<EOT
+exports = (a, b) => {
+	console.log('adding');
+	return { sum: a + b };
+}
+EOT
+	function_args_json = [jsonencode(40), jsonencode(2)]
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunction_eval.test", "id"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunction_eval.test", "result_json"),
+					resource.TestCheckResourceAttr("data.pgrmongodb_appfunction_eval.test", "error", ""),
+				),
+			},
+		},
+	})
+}
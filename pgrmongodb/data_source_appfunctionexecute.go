@@ -2,12 +2,17 @@ package pgrmongodb
 
 import (
 	"context"
+	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -26,14 +31,25 @@ type appFunctionExecuteDataSource struct {
 }
 
 type appFunctionExecuteDataSourceModel struct {
-	ID               types.String `tfsdk:"id"`
-	ProjectID        types.String `tfsdk:"project_id"`
-	AppServicesAppID types.String `tfsdk:"appservices_app_id"`
-	FunctionName     types.String `tfsdk:"function_name"`
-	FunctionArgs     types.Set    `tfsdk:"function_args"`
-	ExecuteNextRun   types.Bool   `tfsdk:"execute_next_run"`
-	ExecutionTimeout types.Int64  `tfsdk:"execution_timeout"`
-	LastRun          types.String `tfsdk:"last_run"`
+	ID                    types.String `tfsdk:"id"`
+	ProjectID             types.String `tfsdk:"project_id"`
+	AppServicesAppID      types.String `tfsdk:"appservices_app_id"`
+	FunctionName          types.String `tfsdk:"function_name"`
+	FunctionArgs          types.Set    `tfsdk:"function_args"`
+	FunctionArgsJSON      types.List   `tfsdk:"function_args_json"`
+	FunctionArgsArrayJSON types.String `tfsdk:"function_args_array_json"`
+	ExecuteNextRun        types.Bool   `tfsdk:"execute_next_run"`
+	ExecutionTimeout      types.Int64  `tfsdk:"execution_timeout"`
+	LastRun               types.String `tfsdk:"last_run"`
+	IgnoreErrors          types.Bool   `tfsdk:"ignore_function_errors"`
+	ResultJSON            types.String `tfsdk:"result_json"`
+	Logs                  types.List   `tfsdk:"logs"`
+	Error                 types.String `tfsdk:"error"`
+	RunAsUserID           types.String `tfsdk:"run_as_user_id"`
+	MaxAttempts           types.Int64  `tfsdk:"max_attempts"`
+	RetryOn               types.Set    `tfsdk:"retry_on"`
+	RetryDelay            types.String `tfsdk:"retry_delay"`
+	Expect                types.List   `tfsdk:"expect"`
 }
 
 func (r *appFunctionExecuteDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -80,6 +96,26 @@ func (r *appFunctionExecuteDataSource) Schema(_ context.Context, _ datasource.Sc
 				Optional:    true,
 				Computed:    true,
 				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.ConflictsWith(path.MatchRoot("function_args_json")),
+					setvalidator.ConflictsWith(path.MatchRoot("function_args_array_json")),
+				},
+			},
+			"function_args_json": schema.ListAttribute{
+				Description: "Ordered list of JSON encoded arguments to pass to the function for execution, for example [jsonencode(42), jsonencode({ dryRun = true })]. Values are sent to the function unchanged.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(jsonStringValidator{}),
+					listvalidator.ConflictsWith(path.MatchRoot("function_args_array_json")),
+				},
+			},
+			"function_args_array_json": schema.StringAttribute{
+				Description: "One JSON array of arguments to pass to the function for execution, for example jsonencode([42, { dryRun = true }]). Each element is sent to the function unchanged, in order.",
+				Optional:    true,
+				Validators: []validator.String{
+					jsonStringValidator{},
+				},
 			},
 			"execute_next_run": schema.BoolAttribute{
 				Description: "Determines if the next Terraform apply will execute the function or not.",
@@ -94,8 +130,41 @@ func (r *appFunctionExecuteDataSource) Schema(_ context.Context, _ datasource.Sc
 				Description: "Timestamp for the last time this function executed successfully.",
 				Computed:    true,
 			},
+			"ignore_function_errors": schema.BoolAttribute{
+				Description: "Do not fail when the function throws or returns an object with an error field. The error is still reported in the error attribute.",
+				Optional:    true,
+			},
+			"run_as_user_id": schema.StringAttribute{
+				Description: "App Services user id to execute the function as, so rules and permissions for that user apply. The function runs as the system user when not set.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal App Services user id",
+					),
+				},
+			},
+			"result_json": schema.StringAttribute{
+				Description: "JSON encoded value returned by the function.",
+				Computed:    true,
+			},
+			"logs": schema.ListAttribute{
+				Description: "Log lines written by the function during execution.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"error": schema.StringAttribute{
+				Description: "Error reported by the function, empty when the function succeeded.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"expect": appFunctionExpectDataSourceBlock(),
 		},
 	}
+	for name, attribute := range appFunctionRetryDataSourceAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
 }
 
 func (r *appFunctionExecuteDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
@@ -121,13 +190,42 @@ func (r *appFunctionExecuteDataSource) Read(ctx context.Context, req datasource.
 	executionTimeout := state.ExecutionTimeout.ValueInt64()
 	diags = state.FunctionArgs.ElementsAs(ctx, &functionArgs, false)
 	resp.Diagnostics.Append(diags...)
+	functionArgsJSON := make([]string, 0, len(state.FunctionArgsJSON.Elements()))
+	diags = state.FunctionArgsJSON.ElementsAs(ctx, &functionArgsJSON, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !state.FunctionArgsArrayJSON.IsNull() {
+		var err error
+		functionArgsJSON, err = appFunctionArgumentsFromArray(state.FunctionArgsArrayJSON.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("function_args_array_json"),
+				"Invalid MongoDB Atlas App Services Function Arguments",
+				err.Error(),
+			)
+			return
+		}
+	}
+	arguments, err := appFunctionArguments(functionArgs, functionArgsJSON)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("function_args_json"),
+			"Invalid MongoDB Atlas App Services Function Arguments",
+			err.Error(),
+		)
+		return
+	}
+	retryPolicy, diags := appFunctionRetryPolicyFromConfig(ctx, state.MaxAttempts, state.RetryOn, state.RetryDelay)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 	if exuecteNextRun {
 		tflog.Info(ctx, "executing mongodb atlas app services function")
 
-		err := executeAppServicesFunctionByName(r.bearer_token, projectID, appServicesAppID, functionName, functionArgs, executionTimeout)
+		execution, err := executeAppServicesFunctionWithRetry(r.bearer_token, projectID, appServicesAppID, functionName, arguments, executionTimeout, state.RunAsUserID.ValueString(), retryPolicy)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Unable to Execute MongoDB Atlas App Services Function",
@@ -135,6 +233,36 @@ func (r *appFunctionExecuteDataSource) Read(ctx context.Context, req datasource.
 			)
 			return
 		}
+		if execution.Error != "" && !state.IgnoreErrors.ValueBool() && !state.RunAsUserID.IsNull() && execution.isPermissionError() {
+			resp.Diagnostics.AddError(
+				"MongoDB Atlas App Services Function Permission Denied",
+				fmt.Sprintf("app function %s was denied for user %s. Check the function's can_evaluate expression and the data access rules for this user: %s\n\nLogs:\n%s", functionName, state.RunAsUserID.ValueString(), execution.Error, strings.Join(execution.Logs, "\n")),
+			)
+			return
+		}
+		if execution.Error != "" && !state.IgnoreErrors.ValueBool() {
+			resp.Diagnostics.AddError(
+				"MongoDB Atlas App Services Function Reported an Error",
+				fmt.Sprintf("app function %s reported an error%s: %s\n\nLogs:\n%s", functionName, execution.attemptsNote(), execution.Error, strings.Join(execution.Logs, "\n")),
+			)
+			return
+		}
+
+		expectations := make([]appFunctionExpectModel, 0, len(state.Expect.Elements()))
+		diags = state.Expect.ElementsAs(ctx, &expectations, false)
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(checkAppFunctionExpectations(functionName, execution.ResultJSON, expectations, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		state.ResultJSON = types.StringValue(execution.ResultJSON)
+		state.Error = types.StringValue(execution.Error)
+		state.Logs, diags = types.ListValueFrom(ctx, types.StringType, execution.Logs)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 
 		currentTime := time.Now().UTC()
 		iso8601Time := currentTime.Format(time.RFC3339)
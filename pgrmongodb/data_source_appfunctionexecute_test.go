@@ -1,6 +1,7 @@
 package pgrmongodb
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -24,8 +25,220 @@ data "pgrmongodb_appfunctionexecute" "test" {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctionexecute.test", "id"),
 					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctionexecute.test", "last_run"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctionexecute.test", "result_json"),
+					resource.TestCheckResourceAttr("data.pgrmongodb_appfunctionexecute.test", "error", ""),
 				),
 			},
 		},
 	})
 }
+
+func TestAccPGRMongoDBAppFunctionExecuteJSONArgs(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+	function_args_json = [jsonencode("tfarg1"), jsonencode(42), jsonencode({ dryRun = true, label = "say \"hi\"" })]
+	execute_next_run = true
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctionexecute.test", "last_run"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctionexecute.test", "result_json"),
+				),
+			},
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+	function_args_json = ["{not json"]
+	execute_next_run = true
+}`,
+				ExpectError: regexp.MustCompile(`Invalid JSON Value`),
+			},
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+	function_args_array_json = jsonencode(["tfarg1", 42, { dryRun = true }])
+	execute_next_run = true
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctionexecute.test", "last_run"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctionexecute.test", "result_json"),
+				),
+			},
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+	function_args_array_json = jsonencode({ dryRun = true })
+	execute_next_run = true
+}`,
+				ExpectError: regexp.MustCompile(`must be one JSON array`),
+			},
+		},
+	})
+}
+
+// note this checks for a user with id 000000000000000000000001 in the app
+func TestAccPGRMongoDBAppFunctionExecuteAsUser(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+	run_as_user_id = "000000000000000000000001"
+	execute_next_run = true
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctionexecute.test", "last_run"),
+					resource.TestCheckResourceAttr("data.pgrmongodb_appfunctionexecute.test", "run_as_user_id", "000000000000000000000001"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPGRMongoDBAppFunctionExecuteRetry(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+	execute_next_run = true
+	max_attempts = 3
+	retry_on = ["timeout", "5xx", "function_error"]
+	retry_delay = "1s"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctionexecute.test", "last_run"),
+					resource.TestCheckResourceAttr("data.pgrmongodb_appfunctionexecute.test", "max_attempts", "3"),
+				),
+			},
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+	execute_next_run = true
+	max_attempts = 3
+	retry_on = ["4xx"]
+}`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+		},
+	})
+}
+
+// note this checks for a function myerrorfunction that always throws and a function myslowfunction that runs
+// for longer than 5 seconds
+func TestAccPGRMongoDBAppFunctionExecuteRetryAttempts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myerrorfunction"
+	execute_next_run = true
+	max_attempts = 2
+	retry_on = ["function_error"]
+	retry_delay = "1s"
+}`,
+				ExpectError: regexp.MustCompile(`app function myerrorfunction reported an error after 2 attempts`),
+			},
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myslowfunction"
+	execute_next_run = true
+	execution_timeout = 5
+	max_attempts = 3
+	retry_on = ["timeout"]
+	retry_delay = "1s"
+}`,
+				ExpectError: regexp.MustCompile(`it may still be running`),
+			},
+		},
+	})
+}
+
+// note this checks for a function myfunction that returns { migrated: <number greater than 0> }
+func TestAccPGRMongoDBAppFunctionExecuteExpect(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+	execute_next_run = true
+
+	expect {
+		path = "$.migrated"
+		greater_than = 0
+	}
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctionexecute.test", "result_json"),
+				),
+			},
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+	execute_next_run = true
+
+	expect {
+		path = "$.migrated"
+		less_than = 0
+	}
+}`,
+				ExpectError: regexp.MustCompile(`expected it to be less than 0`),
+			},
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+	execute_next_run = true
+
+	expect {
+		path = "migrated"
+		equals = jsonencode(1)
+	}
+}`,
+				ExpectError: regexp.MustCompile(`Invalid JSONPath`),
+			},
+		},
+	})
+}
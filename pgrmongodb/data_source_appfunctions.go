@@ -0,0 +1,203 @@
+package pgrmongodb
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &appFunctionsDataSource{}
+)
+
+func NewAppFunctionsDataSource() datasource.DataSource {
+	return &appFunctionsDataSource{}
+}
+
+type appFunctionsDataSource struct {
+	bearer_token string
+}
+
+type appFunctionsDataSourceModel struct {
+	ID               types.String                     `tfsdk:"id"`
+	ProjectID        types.String                     `tfsdk:"project_id"`
+	AppServicesAppID types.String                     `tfsdk:"appservices_app_id"`
+	NamePrefix       types.String                     `tfsdk:"name_prefix"`
+	NameRegex        types.String                     `tfsdk:"name_regex"`
+	Functions        []appFunctionsDataSourceFunction `tfsdk:"functions"`
+}
+
+type appFunctionsDataSourceFunction struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Private      types.Bool   `tfsdk:"private"`
+	RunAsSystem  types.Bool   `tfsdk:"run_as_system"`
+	LastModified types.String `tfsdk:"last_modified"`
+}
+
+func (r *appFunctionsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_appfunctions"
+}
+
+func (r *appFunctionsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data lookup for all MongoDB Atlas App Services Functions in an app",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"project_id": schema.StringAttribute{
+				Description: "MongoDB Atlas project identifier. Sometime referred to as group id.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal project_id",
+					),
+				},
+			},
+			"appservices_app_id": schema.StringAttribute{
+				Description: "MongoDB Atlas App Services app id to list functions from.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal appservices_app_id",
+					),
+				},
+			},
+			"name_prefix": schema.StringAttribute{
+				Description: "Only return functions whose name starts with this prefix.",
+				Optional:    true,
+			},
+			"name_regex": schema.StringAttribute{
+				Description: "Only return functions whose name matches this regular expression.",
+				Optional:    true,
+			},
+			"functions": schema.ListNestedAttribute{
+				Description: "Functions in the app, sorted by name.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "Identifier of the function.",
+							Computed:    true,
+						},
+						"name": schema.StringAttribute{
+							Description: "Name of the function.",
+							Computed:    true,
+						},
+						"private": schema.BoolAttribute{
+							Description: "Whether the function can only be called from other functions and rules.",
+							Computed:    true,
+						},
+						"run_as_system": schema.BoolAttribute{
+							Description: "Whether the function runs as the system user.",
+							Computed:    true,
+						},
+						"last_modified": schema.StringAttribute{
+							Description: "Timestamp for the last time the function was modified.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *appFunctionsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.bearer_token = req.ProviderData.(providerData).bearer_token
+}
+
+func (r *appFunctionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state appFunctionsDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectID := state.ProjectID.ValueString()
+	appServicesAppID := state.AppServicesAppID.ValueString()
+	namePrefix := state.NamePrefix.ValueString()
+
+	var nameRegex *regexp.Regexp
+	if !state.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(state.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name_regex"),
+				"Invalid Function Name Regular Expression",
+				"name_regex is not a valid regular expression: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	tflog.Info(ctx, "listing mongodb atlas app services functions")
+	functions, err := listAppServicesFunctions(r.bearer_token, projectID, appServicesAppID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to List MongoDB Atlas App Services Functions",
+			err.Error(),
+		)
+		return
+	}
+
+	state.Functions = make([]appFunctionsDataSourceFunction, 0, len(functions))
+	for _, v := range functions {
+		functionID, idOk := v["_id"].(string)
+		functionName, nameOk := v["name"].(string)
+		if !idOk || !nameOk {
+			resp.Diagnostics.AddError(
+				"Unable to List MongoDB Atlas App Services Functions",
+				"app services returned a function without an _id or name",
+			)
+			return
+		}
+		if !strings.HasPrefix(functionName, namePrefix) {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(functionName) {
+			continue
+		}
+
+		// everything is taken from the list summary, so listing an app costs a single request
+		state.Functions = append(state.Functions, appFunctionsDataSourceFunction{
+			ID:           types.StringValue(functionID),
+			Name:         types.StringValue(functionName),
+			Private:      types.BoolValue(v["private"] == true),
+			RunAsSystem:  types.BoolValue(v["run_as_system"] == true),
+			LastModified: types.StringValue(appServicesTimestamp(v["last_modified"])),
+		})
+	}
+	sort.Slice(state.Functions, func(i, j int) bool {
+		return state.Functions[i].Name.ValueString() < state.Functions[j].Name.ValueString()
+	})
+
+	state.ID = state.AppServicesAppID
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,30 @@
+package pgrmongodb
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// note this checks for at least 1 function starting with my in the app
+func TestAccPGRMongoDBAppFunctionsDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_appfunctions" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	name_prefix = "my"
+	name_regex = "function$"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctions.test", "id"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctions.test", "functions.0.id"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_appfunctions.test", "functions.0.name"),
+				),
+			},
+		},
+	})
+}
@@ -2,12 +2,14 @@ package pgrmongodb
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -27,11 +29,39 @@ type atlasClusterContainerDataSource struct {
 }
 
 type atlasClusterContainerDataSourceModel struct {
-	ID            types.String `tfsdk:"id"`
-	ProjectID     types.String `tfsdk:"project_id"`
-	CIDRs         types.Map    `tfsdk:"cidrs"`
-	IDs           types.Map    `tfsdk:"ids"`
-	CloudProvider types.String `tfsdk:"cloud_provider"`
+	ID                  types.String                                    `tfsdk:"id"`
+	ProjectID           types.String                                    `tfsdk:"project_id"`
+	CIDRs               types.Map                                       `tfsdk:"cidrs"`
+	IDs                 types.Map                                       `tfsdk:"ids"`
+	CloudProvider       types.String                                    `tfsdk:"cloud_provider"`
+	GCPProjectIDs       types.Map                                       `tfsdk:"gcp_project_ids"`
+	NetworkNames        types.Map                                       `tfsdk:"network_names"`
+	Region              types.String                                    `tfsdk:"region"`
+	Containers          []atlasClusterContainerDataSourceContainerModel `tfsdk:"containers"`
+	ContainerID         types.String                                    `tfsdk:"container_id"`
+	AtlasCIDRBlock      types.String                                    `tfsdk:"atlas_cidr_block"`
+	Provisioned         types.Bool                                      `tfsdk:"provisioned"`
+	VpcID               types.String                                    `tfsdk:"vpc_id"`
+	AzureSubscriptionID types.String                                    `tfsdk:"azure_subscription_id"`
+	VnetName            types.String                                    `tfsdk:"vnet_name"`
+	GCPProjectID        types.String                                    `tfsdk:"gcp_project_id"`
+	NetworkName         types.String                                    `tfsdk:"network_name"`
+}
+
+type atlasClusterContainerDataSourceContainerModel struct {
+	ID                  types.String `tfsdk:"id"`
+	ProviderName        types.String `tfsdk:"provider_name"`
+	AtlasCIDRBlock      types.String `tfsdk:"atlas_cidr_block"`
+	Provisioned         types.Bool   `tfsdk:"provisioned"`
+	Region              types.String `tfsdk:"region"`
+	AzureRegion         types.String `tfsdk:"azure_region"`
+	Regions             types.List   `tfsdk:"regions"`
+	Keys                types.List   `tfsdk:"keys"`
+	VpcID               types.String `tfsdk:"vpc_id"`
+	AzureSubscriptionID types.String `tfsdk:"azure_subscription_id"`
+	VnetName            types.String `tfsdk:"vnet_name"`
+	GCPProjectID        types.String `tfsdk:"gcp_project_id"`
+	NetworkName         types.String `tfsdk:"network_name"`
 }
 
 func (r *atlasClusterContainerDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -65,7 +95,7 @@ func (r *atlasClusterContainerDataSource) Schema(_ context.Context, _ datasource
 				},
 			},
 			"cidrs": schema.MapAttribute{
-				Description: "Map of container cidrs by region.",
+				Description: "Map of container cidrs by region. Keys are provider:region, for example AWS:US_EAST_1. GCP containers are global and are listed under each of their regions, or under GCP:GLOBAL when they have none.",
 				ElementType: types.StringType,
 				Computed:    true,
 			},
@@ -74,6 +104,114 @@ func (r *atlasClusterContainerDataSource) Schema(_ context.Context, _ datasource
 				ElementType: types.StringType,
 				Computed:    true,
 			},
+			"gcp_project_ids": schema.MapAttribute{
+				Description: "Map of GCP project ids of the Atlas VPCs by region. Only set for GCP.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"network_names": schema.MapAttribute{
+				Description: "Map of GCP network names of the Atlas VPCs by region. Only set for GCP.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Only return the container serving this region and set the single-container attributes from it. Accepts the Atlas spelling such as US_EAST_2 or the cloud provider spelling such as eastus2, us-east4 or us-east-1. Use GLOBAL to select a GCP container that does not list regions.",
+				Optional:    true,
+			},
+			"containers": schema.ListNestedAttribute{
+				Description: "Network containers of the cloud provider, filtered by region when set.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "Identifier of the container.",
+							Computed:    true,
+						},
+						"provider_name": schema.StringAttribute{
+							Description: "Cloud provider of the container.",
+							Computed:    true,
+						},
+						"atlas_cidr_block": schema.StringAttribute{
+							Description: "CIDR block of the Atlas VPC or VNet.",
+							Computed:    true,
+						},
+						"provisioned": schema.BoolAttribute{
+							Description: "Whether Atlas has deployed clusters into the container.",
+							Computed:    true,
+						},
+						"region": schema.StringAttribute{
+							Description: "Atlas region name of AWS and Azure containers. Not set for GCP.",
+							Computed:    true,
+						},
+						"azure_region": schema.StringAttribute{
+							Description: "Azure spelling of region, for example eastus2. Only set for Azure.",
+							Computed:    true,
+						},
+						"regions": schema.ListAttribute{
+							Description: "Atlas region names the container serves. Empty for global GCP containers.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"keys": schema.ListAttribute{
+							Description: "provider:region keys of the container in the ids and cidrs maps.",
+							ElementType: types.StringType,
+							Computed:    true,
+						},
+						"vpc_id": schema.StringAttribute{
+							Description: "AWS VPC id of the Atlas VPC. Only set for AWS.",
+							Computed:    true,
+						},
+						"azure_subscription_id": schema.StringAttribute{
+							Description: "Azure subscription id of the Atlas VNet. Only set for Azure.",
+							Computed:    true,
+						},
+						"vnet_name": schema.StringAttribute{
+							Description: "Name of the Atlas VNet. Only set for Azure.",
+							Computed:    true,
+						},
+						"gcp_project_id": schema.StringAttribute{
+							Description: "GCP project id of the Atlas VPC. Only set for GCP.",
+							Computed:    true,
+						},
+						"network_name": schema.StringAttribute{
+							Description: "GCP network name of the Atlas VPC. Only set for GCP.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"container_id": schema.StringAttribute{
+				Description: "Identifier of the container serving region. Only set when region is set.",
+				Computed:    true,
+			},
+			"atlas_cidr_block": schema.StringAttribute{
+				Description: "CIDR block of the container serving region. Only set when region is set.",
+				Computed:    true,
+			},
+			"provisioned": schema.BoolAttribute{
+				Description: "Whether Atlas has deployed clusters into the container serving region. Only set when region is set.",
+				Computed:    true,
+			},
+			"vpc_id": schema.StringAttribute{
+				Description: "AWS VPC id of the container serving region. Only set when region is set.",
+				Computed:    true,
+			},
+			"azure_subscription_id": schema.StringAttribute{
+				Description: "Azure subscription id of the container serving region. Only set when region is set.",
+				Computed:    true,
+			},
+			"vnet_name": schema.StringAttribute{
+				Description: "Azure VNet name of the container serving region. Only set when region is set.",
+				Computed:    true,
+			},
+			"gcp_project_id": schema.StringAttribute{
+				Description: "GCP project id of the container serving region. Only set when region is set.",
+				Computed:    true,
+			},
+			"network_name": schema.StringAttribute{
+				Description: "GCP network name of the container serving region. Only set when region is set.",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -98,7 +236,7 @@ func (r *atlasClusterContainerDataSource) Read(ctx context.Context, req datasour
 	projectID := state.ProjectID.ValueString()
 	cloudProvider := state.CloudProvider.ValueString()
 	tflog.Info(ctx, "reading mongodb atlas cluster network container")
-	ids, cidrs, err := getClusterContainers(r.public_key, r.private_key, projectID, cloudProvider)
+	containers, err := getClusterContainers(r.public_key, r.private_key, projectID, cloudProvider)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Get MongoDB Atlas Cluster Containers",
@@ -108,12 +246,18 @@ func (r *atlasClusterContainerDataSource) Read(ctx context.Context, req datasour
 	}
 
 	idElements := make(map[string]attr.Value)
-	for key, value := range ids {
-		idElements[key] = types.StringValue(value)
-	}
 	cidrElements := make(map[string]attr.Value)
-	for key, value := range cidrs {
-		cidrElements[key] = types.StringValue(value)
+	gcpProjectIDElements := make(map[string]attr.Value)
+	networkNameElements := make(map[string]attr.Value)
+	for _, container := range containers {
+		for _, key := range container.keys() {
+			idElements[key] = types.StringValue(container.ID)
+			cidrElements[key] = types.StringValue(container.AtlasCIDRBlock)
+			if container.ProviderName == "GCP" {
+				gcpProjectIDElements[key] = types.StringValue(container.GCPProjectID)
+				networkNameElements[key] = types.StringValue(container.NetworkName)
+			}
+		}
 	}
 
 	ids_map, diags := types.MapValue(types.StringType, idElements)
@@ -126,10 +270,92 @@ func (r *atlasClusterContainerDataSource) Read(ctx context.Context, req datasour
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	gcp_project_ids_map, diags := types.MapValue(types.StringType, gcpProjectIDElements)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	network_names_map, diags := types.MapValue(types.StringType, networkNameElements)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the maps always list every container, the region filter only applies to containers and the single-container attributes
+	if !state.Region.IsNull() {
+		filtered := make([]clusterContainer, 0, 1)
+		for _, container := range containers {
+			if container.servesRegion(state.Region.ValueString()) {
+				filtered = append(filtered, container)
+			}
+		}
+		if len(filtered) != 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("region"),
+				"Unable to Find MongoDB Atlas Cluster Container",
+				fmt.Sprintf("Expected 1 %s network container for region %s, found %d.", cloudProvider, state.Region.ValueString(), len(filtered)),
+			)
+			return
+		}
+		containers = filtered
+
+		state.ContainerID = types.StringValue(filtered[0].ID)
+		state.AtlasCIDRBlock = types.StringValue(filtered[0].AtlasCIDRBlock)
+		state.Provisioned = types.BoolValue(filtered[0].Provisioned)
+		state.VpcID = optionalStringValue(filtered[0].VpcID)
+		state.AzureSubscriptionID = optionalStringValue(filtered[0].AzureSubscriptionID)
+		state.VnetName = optionalStringValue(filtered[0].VnetName)
+		state.GCPProjectID = optionalStringValue(filtered[0].GCPProjectID)
+		state.NetworkName = optionalStringValue(filtered[0].NetworkName)
+	} else {
+		state.ContainerID = types.StringNull()
+		state.AtlasCIDRBlock = types.StringNull()
+		state.Provisioned = types.BoolNull()
+		state.VpcID = types.StringNull()
+		state.AzureSubscriptionID = types.StringNull()
+		state.VnetName = types.StringNull()
+		state.GCPProjectID = types.StringNull()
+		state.NetworkName = types.StringNull()
+	}
+
+	state.Containers = make([]atlasClusterContainerDataSourceContainerModel, 0, len(containers))
+	for _, container := range containers {
+		regions, diags := types.ListValueFrom(ctx, types.StringType, container.Regions)
+		resp.Diagnostics.Append(diags...)
+		keys, diags := types.ListValueFrom(ctx, types.StringType, container.keys())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		model := atlasClusterContainerDataSourceContainerModel{
+			ID:                  types.StringValue(container.ID),
+			ProviderName:        types.StringValue(container.ProviderName),
+			AtlasCIDRBlock:      types.StringValue(container.AtlasCIDRBlock),
+			Provisioned:         types.BoolValue(container.Provisioned),
+			Region:              types.StringNull(),
+			AzureRegion:         types.StringNull(),
+			Regions:             regions,
+			Keys:                keys,
+			VpcID:               optionalStringValue(container.VpcID),
+			AzureSubscriptionID: optionalStringValue(container.AzureSubscriptionID),
+			VnetName:            optionalStringValue(container.VnetName),
+			GCPProjectID:        optionalStringValue(container.GCPProjectID),
+			NetworkName:         optionalStringValue(container.NetworkName),
+		}
+		if container.ProviderName != "GCP" && len(container.Regions) == 1 {
+			model.Region = types.StringValue(container.Regions[0])
+		}
+		if container.ProviderName == "AZURE" {
+			model.AzureRegion = optionalStringValue(azureRegionName(container.Regions[0]))
+		}
+		state.Containers = append(state.Containers, model)
+	}
 
 	state.ID = state.ProjectID
 	state.IDs = ids_map
 	state.CIDRs = cidrs_map
+	state.GCPProjectIDs = gcp_project_ids_map
+	state.NetworkNames = network_names_map
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -137,3 +363,11 @@ func (r *atlasClusterContainerDataSource) Read(ctx context.Context, req datasour
 		return
 	}
 }
+
+// returns a null string for empty values, for container fields that only apply to some cloud providers
+func optionalStringValue(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
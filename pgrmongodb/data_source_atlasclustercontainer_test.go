@@ -1,6 +1,7 @@
 package pgrmongodb
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -20,6 +21,57 @@ data "pgrmongodb_atlasclustercontainer" "test" {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("data.pgrmongodb_atlasclustercontainer.test", "id"),
 					resource.TestCheckResourceAttrSet("data.pgrmongodb_atlasclustercontainer.test", "project_id"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_atlasclustercontainer.test", "containers.0.id"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_atlasclustercontainer.test", "containers.0.keys.0"),
+					resource.TestCheckNoResourceAttr("data.pgrmongodb_atlasclustercontainer.test", "container_id"),
+				),
+			},
+		},
+	})
+}
+
+// note this checks for an Azure network container in US_EAST_2, selected by its Azure region name
+func TestAccPGRMongoDBAtlasContainersRegion(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_atlasclustercontainer" "test" {
+	project_id = "000000000000000000000000"
+	cloud_provider = "AZURE"
+	region = "eastus2"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pgrmongodb_atlasclustercontainer.test", "containers.#", "1"),
+					resource.TestCheckResourceAttr("data.pgrmongodb_atlasclustercontainer.test", "containers.0.region", "US_EAST_2"),
+					resource.TestCheckResourceAttr("data.pgrmongodb_atlasclustercontainer.test", "containers.0.azure_region", "eastus2"),
+					resource.TestCheckResourceAttrPair("data.pgrmongodb_atlasclustercontainer.test", "container_id", "data.pgrmongodb_atlasclustercontainer.test", "ids.AZURE:US_EAST_2"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_atlasclustercontainer.test", "atlas_cidr_block"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_atlasclustercontainer.test", "azure_subscription_id"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_atlasclustercontainer.test", "vnet_name"),
+				),
+			},
+		},
+	})
+}
+
+// note this checks for a GCP network container in the project
+func TestAccPGRMongoDBAtlasContainersGCP(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_atlasclustercontainer" "test" {
+	project_id = "000000000000000000000000"
+	cloud_provider = "GCP"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_atlasclustercontainer.test", "id"),
+					resource.TestMatchResourceAttr("data.pgrmongodb_atlasclustercontainer.test", "ids.%", regexp.MustCompile(`^[1-9]`)),
+					resource.TestMatchResourceAttr("data.pgrmongodb_atlasclustercontainer.test", "gcp_project_ids.%", regexp.MustCompile(`^[1-9]`)),
+					resource.TestMatchResourceAttr("data.pgrmongodb_atlasclustercontainer.test", "network_names.%", regexp.MustCompile(`^[1-9]`)),
 				),
 			},
 		},
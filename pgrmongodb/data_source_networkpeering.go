@@ -0,0 +1,222 @@
+package pgrmongodb
+
+import (
+	"context"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &networkPeeringDataSource{}
+)
+
+func NewNetworkPeeringDataSource() datasource.DataSource {
+	return &networkPeeringDataSource{}
+}
+
+type networkPeeringDataSource struct {
+	public_key  string
+	private_key string
+}
+
+type networkPeeringDataSourceModel struct {
+	ID            types.String                      `tfsdk:"id"`
+	ProjectID     types.String                      `tfsdk:"project_id"`
+	CloudProvider types.String                      `tfsdk:"cloud_provider"`
+	ContainerID   types.String                      `tfsdk:"container_id"`
+	Peerings      []networkPeeringDataSourcePeering `tfsdk:"peerings"`
+}
+
+type networkPeeringDataSourcePeering struct {
+	ID                  types.String `tfsdk:"id"`
+	ContainerID         types.String `tfsdk:"container_id"`
+	Status              types.String `tfsdk:"status"`
+	ErrorMessage        types.String `tfsdk:"error_message"`
+	AccepterRegionName  types.String `tfsdk:"accepter_region_name"`
+	AWSAccountID        types.String `tfsdk:"aws_account_id"`
+	RouteTableCIDRBlock types.String `tfsdk:"route_table_cidr_block"`
+	VpcID               types.String `tfsdk:"vpc_id"`
+	ConnectionID        types.String `tfsdk:"connection_id"`
+	AzureDirectoryID    types.String `tfsdk:"azure_directory_id"`
+	AzureSubscriptionID types.String `tfsdk:"azure_subscription_id"`
+	ResourceGroupName   types.String `tfsdk:"resource_group_name"`
+	VnetName            types.String `tfsdk:"vnet_name"`
+	GCPProjectID        types.String `tfsdk:"gcp_project_id"`
+	NetworkName         types.String `tfsdk:"network_name"`
+}
+
+func (r *networkPeeringDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_networkpeering"
+}
+
+func (r *networkPeeringDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data lookup for MongoDB Atlas network peering connections",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"project_id": schema.StringAttribute{
+				Description: "MongoDB Atlas project identifier. Sometime referred to as group id.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal project_id",
+					),
+				},
+			},
+			"cloud_provider": schema.StringAttribute{
+				Description: "MongoDB Atlas cloud provider to retrieve network peering connections for.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"AWS", "GCP", "AZURE"}...),
+				},
+			},
+			"container_id": schema.StringAttribute{
+				Description: "Only return peering connections of this Atlas network container.",
+				Optional:    true,
+			},
+			"peerings": schema.ListNestedAttribute{
+				Description: "Network peering connections, sorted by id.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "Identifier of the peering connection.",
+							Computed:    true,
+						},
+						"container_id": schema.StringAttribute{
+							Description: "Identifier of the Atlas network container.",
+							Computed:    true,
+						},
+						"status": schema.StringAttribute{
+							Description: "Status of the peering connection.",
+							Computed:    true,
+						},
+						"error_message": schema.StringAttribute{
+							Description: "Error reported by Atlas for a failed peering connection. Null when the connection has no error.",
+							Computed:    true,
+						},
+						"accepter_region_name": schema.StringAttribute{
+							Description: "AWS region of the peer VPC. Only set for AWS.",
+							Computed:    true,
+						},
+						"aws_account_id": schema.StringAttribute{
+							Description: "AWS account id of the owner of the peer VPC. Only set for AWS.",
+							Computed:    true,
+						},
+						"route_table_cidr_block": schema.StringAttribute{
+							Description: "CIDR block of the peer VPC that Atlas routes to. Only set for AWS.",
+							Computed:    true,
+						},
+						"vpc_id": schema.StringAttribute{
+							Description: "Identifier of the peer AWS VPC. Only set for AWS.",
+							Computed:    true,
+						},
+						"connection_id": schema.StringAttribute{
+							Description: "AWS VPC peering connection id. Only set for AWS.",
+							Computed:    true,
+						},
+						"azure_directory_id": schema.StringAttribute{
+							Description: "Azure Active Directory tenant id of the peer VNet. Only set for Azure.",
+							Computed:    true,
+						},
+						"azure_subscription_id": schema.StringAttribute{
+							Description: "Azure subscription id of the peer VNet. Only set for Azure.",
+							Computed:    true,
+						},
+						"resource_group_name": schema.StringAttribute{
+							Description: "Azure resource group of the peer VNet. Only set for Azure.",
+							Computed:    true,
+						},
+						"vnet_name": schema.StringAttribute{
+							Description: "Name of the peer Azure VNet. Only set for Azure.",
+							Computed:    true,
+						},
+						"gcp_project_id": schema.StringAttribute{
+							Description: "GCP project id of the peer VPC. Only set for GCP.",
+							Computed:    true,
+						},
+						"network_name": schema.StringAttribute{
+							Description: "Name of the peer GCP VPC network. Only set for GCP.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *networkPeeringDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.public_key = req.ProviderData.(providerData).public_key
+	r.private_key = req.ProviderData.(providerData).private_key
+}
+
+func (r *networkPeeringDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state networkPeeringDataSourceModel
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "reading mongodb atlas network peering connections")
+	peerings, err := listNetworkPeerings(r.public_key, r.private_key, state.ProjectID.ValueString(), state.CloudProvider.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to List MongoDB Atlas Network Peering Connections",
+			err.Error(),
+		)
+		return
+	}
+	sort.Slice(peerings, func(i, j int) bool {
+		return peerings[i].ID < peerings[j].ID
+	})
+
+	state.Peerings = make([]networkPeeringDataSourcePeering, 0, len(peerings))
+	for _, v := range peerings {
+		if !state.ContainerID.IsNull() && v.ContainerID != state.ContainerID.ValueString() {
+			continue
+		}
+		state.Peerings = append(state.Peerings, networkPeeringDataSourcePeering{
+			ID:                  types.StringValue(v.ID),
+			ContainerID:         types.StringValue(v.ContainerID),
+			Status:              types.StringValue(v.Status),
+			ErrorMessage:        optionalStringValue(v.ErrorMessage),
+			AccepterRegionName:  optionalStringValue(v.AccepterRegionName),
+			AWSAccountID:        optionalStringValue(v.AWSAccountID),
+			RouteTableCIDRBlock: optionalStringValue(v.RouteTableCIDRBlock),
+			VpcID:               optionalStringValue(v.VpcID),
+			ConnectionID:        optionalStringValue(v.ConnectionID),
+			AzureDirectoryID:    optionalStringValue(v.AzureDirectoryID),
+			AzureSubscriptionID: optionalStringValue(v.AzureSubscriptionID),
+			ResourceGroupName:   optionalStringValue(v.ResourceGroupName),
+			VnetName:            optionalStringValue(v.VnetName),
+			GCPProjectID:        optionalStringValue(v.GCPProjectID),
+			NetworkName:         optionalStringValue(v.NetworkName),
+		})
+	}
+
+	state.ID = state.ProjectID
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,30 @@
+package pgrmongodb
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// note this checks for at least 1 AWS network peering connection in the project
+func TestAccPGRMongoDBNetworkPeeringDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+data "pgrmongodb_networkpeering" "test" {
+	project_id = "000000000000000000000000"
+	cloud_provider = "AWS"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_networkpeering.test", "id"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_networkpeering.test", "peerings.0.id"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_networkpeering.test", "peerings.0.container_id"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_networkpeering.test", "peerings.0.status"),
+					resource.TestCheckResourceAttrSet("data.pgrmongodb_networkpeering.test", "peerings.0.vpc_id"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,210 @@
+package pgrmongodb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &appFunctionExecuteEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &appFunctionExecuteEphemeralResource{}
+)
+
+func NewAppFunctionExecuteEphemeralResource() ephemeral.EphemeralResource {
+	return &appFunctionExecuteEphemeralResource{}
+}
+
+type appFunctionExecuteEphemeralResource struct {
+	bearer_token string
+}
+
+type appFunctionExecuteEphemeralResourceModel struct {
+	ProjectID        types.String `tfsdk:"project_id"`
+	AppServicesAppID types.String `tfsdk:"appservices_app_id"`
+	FunctionName     types.String `tfsdk:"function_name"`
+	FunctionArgsJSON types.List   `tfsdk:"function_args_json"`
+	ExecutionTimeout types.Int64  `tfsdk:"execution_timeout"`
+	IgnoreErrors     types.Bool   `tfsdk:"ignore_function_errors"`
+	RunAsUserID      types.String `tfsdk:"run_as_user_id"`
+	MaxAttempts      types.Int64  `tfsdk:"max_attempts"`
+	RetryOn          types.Set    `tfsdk:"retry_on"`
+	RetryDelay       types.String `tfsdk:"retry_delay"`
+	Expect           types.List   `tfsdk:"expect"`
+	ResultJSON       types.String `tfsdk:"result_json"`
+	Logs             types.List   `tfsdk:"logs"`
+	Error            types.String `tfsdk:"error"`
+}
+
+func (r *appFunctionExecuteEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_appfunctionexecute"
+}
+
+func (r *appFunctionExecuteEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Executes a MongoDB Atlas App Services Function without storing the result in plan or state. Terraform opens ephemeral resources during plan as well as apply, so the function runs on every plan and again on apply. A function that generates credentials creates new ones each time",
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				Description: "MongoDB Atlas project identifier. Sometime referred to as group id.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal project_id",
+					),
+				},
+			},
+			"appservices_app_id": schema.StringAttribute{
+				Description: "MongoDB Atlas App Services app id to execute the function in.",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal appservices_app_id",
+					),
+				},
+			},
+			"function_name": schema.StringAttribute{
+				Description: "Name of function to execute.",
+				Required:    true,
+			},
+			"function_args_json": schema.ListAttribute{
+				Description: "Ordered list of JSON encoded arguments to pass to the function for execution, for example [jsonencode(42), jsonencode({ dryRun = true })]. Values are sent to the function unchanged.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(jsonStringValidator{}),
+				},
+			},
+			"execution_timeout": schema.Int64Attribute{
+				Description: "Sets the timeout value for function invocation (seconds).",
+				Optional:    true,
+			},
+			"ignore_function_errors": schema.BoolAttribute{
+				Description: "Do not fail when the function throws or returns an object with an error field. The error is still reported in the error attribute.",
+				Optional:    true,
+			},
+			"run_as_user_id": schema.StringAttribute{
+				Description: "App Services user id to execute the function as, so rules and permissions for that user apply. The function runs as the system user when not set.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal App Services user id",
+					),
+				},
+			},
+			"result_json": schema.StringAttribute{
+				Description: "JSON encoded value returned by the function.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"logs": schema.ListAttribute{
+				Description: "Log lines written by the function during execution.",
+				ElementType: types.StringType,
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"error": schema.StringAttribute{
+				Description: "Error reported by the function, empty when the function succeeded.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"expect": appFunctionExpectEphemeralBlock(),
+		},
+	}
+	for name, attribute := range appFunctionRetryEphemeralAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+func (r *appFunctionExecuteEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, _ *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	r.bearer_token = req.ProviderData.(providerData).bearer_token
+}
+
+func (r *appFunctionExecuteEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data appFunctionExecuteEphemeralResourceModel
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	functionArgsJSON := make([]string, 0, len(data.FunctionArgsJSON.Elements()))
+	diags = data.FunctionArgsJSON.ElementsAs(ctx, &functionArgsJSON, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	arguments, err := appFunctionArguments(nil, functionArgsJSON)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("function_args_json"),
+			"Invalid MongoDB Atlas App Services Function Arguments",
+			err.Error(),
+		)
+		return
+	}
+	retryPolicy, diags := appFunctionRetryPolicyFromConfig(ctx, data.MaxAttempts, data.RetryOn, data.RetryDelay)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "executing mongodb atlas app services function for ephemeral result")
+	functionName := data.FunctionName.ValueString()
+	execution, err := executeAppServicesFunctionWithRetry(r.bearer_token, data.ProjectID.ValueString(), data.AppServicesAppID.ValueString(), functionName, arguments, data.ExecutionTimeout.ValueInt64(), data.RunAsUserID.ValueString(), retryPolicy)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Execute MongoDB Atlas App Services Function",
+			err.Error(),
+		)
+		return
+	}
+	// logs are left out of the diagnostic since they may contain the secret the function produces
+	if execution.Error != "" && !data.IgnoreErrors.ValueBool() {
+		resp.Diagnostics.AddError(
+			"MongoDB Atlas App Services Function Reported an Error",
+			fmt.Sprintf("app function %s reported an error%s: %s", functionName, execution.attemptsNote(), strings.TrimSpace(execution.Error)),
+		)
+		return
+	}
+
+	// failed checks do not show the result, it is as sensitive as result_json
+	expectations := make([]appFunctionExpectModel, 0, len(data.Expect.Elements()))
+	diags = data.Expect.ElementsAs(ctx, &expectations, false)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(checkAppFunctionExpectations(functionName, execution.ResultJSON, expectations, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ResultJSON = types.StringValue(execution.ResultJSON)
+	data.Error = types.StringValue(execution.Error)
+	data.Logs, diags = types.ListValueFrom(ctx, types.StringType, execution.Logs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.Result.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,56 @@
+package pgrmongodb
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// note this checks for a function myfunction in the app that returns its first argument. The echo provider copies the ephemeral
+// result into a test resource so it can be checked
+func TestAccPGRMongoDBAppFunctionExecuteEphemeral(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"pgrmongodb": providerserver.NewProtocol6WithError(New()),
+			"echo":       echoprovider.NewProviderServer(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+ephemeral "pgrmongodb_appfunctionexecute" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	function_name = "myfunction"
+	function_args_json = [jsonencode("tfarg1")]
+	max_attempts = 2
+	retry_on = ["5xx"]
+
+	expect {
+		path = "$"
+		equals = jsonencode("tfarg1")
+	}
+}
+
+provider "echo" {
+	data = ephemeral.pgrmongodb_appfunctionexecute.test.error
+}
+
+resource "echo" "test" {}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("echo.test", tfjsonpath.New("data"), knownvalue.StringExact("")),
+				},
+			},
+		},
+	})
+}
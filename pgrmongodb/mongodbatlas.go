@@ -1,16 +1,20 @@
 package pgrmongodb
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
-	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/Masterminds/semver/v3"
 )
 
 // APP SERVICES APP
@@ -113,43 +117,68 @@ func deleteAppServicesApp(bearer_token string, projectID string, appID string) e
 
 // APP SERVICES FUNCTION
 
-func getAppServicesFunctionByName(bearer_token string, projectID string, appServicesAppID string, functionName string) (string, string, error) {
-	found_function_id := ""
-	found_function_code := ""
-
+func listAppServicesFunctions(bearer_token string, projectID string, appServicesAppID string) ([]map[string]interface{}, error) {
 	r, err := httpRequestWithBearerAuth(bearer_token, "GET", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/functions", projectID, appServicesAppID), "", 10)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 	defer r.Body.Close()
-	apps, err := responseToArrayOfMap(r)
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to list app functions. Got statuscode: %d", r.StatusCode)
+	}
+	return responseToArrayOfMap(r)
+}
+
+func getAppServicesFunctionIDByName(bearer_token string, projectID string, appServicesAppID string, functionName string) (string, error) {
+	functions, err := listAppServicesFunctions(bearer_token, projectID, appServicesAppID)
 	if err != nil {
-		return "", "", err
+		return "", err
 	}
 
-	for _, v := range apps {
+	for _, v := range functions {
 		if v["name"] == functionName {
-			found_function_id = v["_id"].(string)
-			_, found_function_code, err = getAppServicesFunctionByID(bearer_token, projectID, appServicesAppID, found_function_id)
-			if err != nil {
-				return "", "", err
-			}
-			break
+			return v["_id"].(string), nil
 		}
 	}
-	if found_function_id == "" {
-		err = fmt.Errorf("app function %s does not exist", functionName)
+	return "", fmt.Errorf("app function %s does not exist", functionName)
+}
+
+// returns the list entry (_id, name, last_modified) of the function with the given id, or with the given name when no id is given.
+// last_modified is only part of the list response, the single function GET does not return it
+func getAppServicesFunctionSummary(bearer_token string, projectID string, appServicesAppID string, functionID string, functionName string) (map[string]interface{}, error) {
+	functions, err := listAppServicesFunctions(bearer_token, projectID, appServicesAppID)
+	if err != nil {
+		return nil, err
 	}
-	return found_function_id, found_function_code, err
+
+	for _, v := range functions {
+		if functionID != "" && v["_id"] == functionID {
+			return v, nil
+		}
+		if functionID == "" && v["name"] == functionName {
+			return v, nil
+		}
+	}
+	if functionID != "" {
+		return nil, fmt.Errorf("app function %s does not exist", functionID)
+	}
+	return nil, fmt.Errorf("app function %s does not exist", functionName)
 }
 
-func getAppServicesFunctionByID(bearer_token string, projectID string, appServicesAppID string, functionID string) (string, string, error) {
-	r, err := httpRequestWithBearerAuth(bearer_token, "GET", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/functions/%s", projectID, appServicesAppID, functionID), "", 10)
+func getAppServicesFunctionByName(bearer_token string, projectID string, appServicesAppID string, functionName string) (string, string, error) {
+	found_function_id, err := getAppServicesFunctionIDByName(bearer_token, projectID, appServicesAppID, functionName)
 	if err != nil {
 		return "", "", err
 	}
-	defer r.Body.Close()
-	respjson, err := responseToMap(r)
+	_, found_function_code, err := getAppServicesFunctionByID(bearer_token, projectID, appServicesAppID, found_function_id)
+	if err != nil {
+		return "", "", err
+	}
+	return found_function_id, found_function_code, err
+}
+
+func getAppServicesFunctionByID(bearer_token string, projectID string, appServicesAppID string, functionID string) (string, string, error) {
+	respjson, err := getAppServicesFunctionDetailsByID(bearer_token, projectID, appServicesAppID, functionID)
 	if err != nil {
 		return "", "", err
 	}
@@ -159,52 +188,371 @@ func getAppServicesFunctionByID(bearer_token string, projectID string, appServic
 	return found_function_name, found_function_code, err
 }
 
-func executeAppServicesFunctionByName(bearer_token string, projectID string, appServicesAppID string, functionName string, functionArgs []string, executionTimeout int64) error {
-	var jsonStr string
+// returns the full function document (_id, name, source, private, run_as_system, ...)
+func getAppServicesFunctionDetailsByID(bearer_token string, projectID string, appServicesAppID string, functionID string) (map[string]interface{}, error) {
+	r, err := httpRequestWithBearerAuth(bearer_token, "GET", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/functions/%s", projectID, appServicesAppID, functionID), "", 10)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to get app function %s. Got statuscode: %d", functionID, r.StatusCode)
+	}
+	return responseToMap(r)
+}
 
-	if len(functionArgs) == 0 {
-		jsonStr = fmt.Sprintf(`{
-			"name": "%s",
-			"arguments": []
+// outcome of a function execution. Error is set when the function threw or returned an object with an error field
+type appFunctionExecution struct {
+	ResultJSON string
+	Logs       []string
+	Error      string
+	ErrorCode  string
+	Attempts   int64
+}
+
+// describes the number of attempts for error messages, empty when the function ran once
+func (e *appFunctionExecution) attemptsNote() string {
+	if e.Attempts <= 1 {
+		return ""
+	}
+	return fmt.Sprintf(" after %d attempts", e.Attempts)
+}
+
+// builds execute_function arguments from JSON encoded values, or from plain strings when no JSON values are given
+func appFunctionArguments(functionArgs []string, functionArgsJSON []string) ([]json.RawMessage, error) {
+	arguments := make([]json.RawMessage, 0, len(functionArgs)+len(functionArgsJSON))
+	for i, v := range functionArgsJSON {
+		if !json.Valid([]byte(v)) {
+			return nil, fmt.Errorf("function argument %d is not valid JSON: %s", i, v)
 		}
-		`, functionName)
-	} else {
-		jsonStr = fmt.Sprintf(`{
-			"name": "%s",
-			"arguments": ["%v"]
+		arguments = append(arguments, json.RawMessage(v))
+	}
+	for _, v := range functionArgs {
+		argBytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
 		}
-		`, functionName, strings.Join(functionArgs, "\", \""))
+		arguments = append(arguments, json.RawMessage(argBytes))
 	}
+	return arguments, nil
+}
+
+// splits one JSON array into its JSON encoded elements, keeping their order
+func appFunctionArgumentsFromArray(arrayJSON string) ([]string, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal([]byte(arrayJSON), &elements); err != nil || elements == nil {
+		return nil, fmt.Errorf("function arguments must be one JSON array, for example jsonencode([42, true]): %s", arrayJSON)
+	}
+	functionArgsJSON := make([]string, 0, len(elements))
+	for _, v := range elements {
+		functionArgsJSON = append(functionArgsJSON, string(v))
+	}
+	return functionArgsJSON, nil
+}
+
+// executes a function as the system user, or as the App Services user runAsUserID when it is set
+func executeAppServicesFunctionByName(bearer_token string, projectID string, appServicesAppID string, functionName string, functionArgs []json.RawMessage, executionTimeout int64, runAsUserID string) (*appFunctionExecution, error) {
+	jsonBytes, err := json.Marshal(map[string]interface{}{
+		"name":      functionName,
+		"arguments": functionArgs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	jsonStr := string(jsonBytes)
 
 	if executionTimeout == 0 {
 		executionTimeout = 10
 	}
 
-	r, err := httpRequestWithBearerAuth(bearer_token, "POST", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/debug/execute_function?run_as_system=true", projectID, appServicesAppID), jsonStr, executionTimeout)
+	query := "run_as_system=true"
+	if runAsUserID != "" {
+		query = "user_id=" + url.QueryEscape(runAsUserID)
+	}
+
+	r, err := httpRequestWithBearerAuth(bearer_token, "POST", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/debug/execute_function?%s", projectID, appServicesAppID, query), jsonStr, executionTimeout)
 	if err != nil {
-		return err
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, &appFunctionTimeoutError{FunctionName: functionName, ExecutionTimeout: executionTimeout}
+		}
+		return nil, err
 	}
 	defer r.Body.Close()
 
-	if r.StatusCode == http.StatusOK {
-		return nil
-	} else {
-		return fmt.Errorf("unable to execute app function %s. Got statuscode: %d. Using json: %s", functionName, r.StatusCode, jsonStr)
+	execution, parseErr := responseToAppFunctionExecution(r)
+	if r.StatusCode == http.StatusOK && parseErr == nil {
+		return execution, nil
+	}
+	// a function that throws is reported with a 4xx status and the error in the body
+	if parseErr == nil && execution.Error != "" {
+		return execution, nil
+	}
+	if runAsUserID != "" && (r.StatusCode == http.StatusUnauthorized || r.StatusCode == http.StatusForbidden) {
+		return nil, fmt.Errorf("unable to execute app function %s as user %s, the user is not allowed to call the function. Got statuscode: %d", functionName, runAsUserID, r.StatusCode)
+	}
+	return nil, &appFunctionStatusError{
+		StatusCode: r.StatusCode,
+		Message:    fmt.Sprintf("unable to execute app function %s. Got statuscode: %d. Using json: %s", functionName, r.StatusCode, jsonStr),
+	}
+}
+
+// returned when execute_function answers with an unexpected status code
+type appFunctionStatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *appFunctionStatusError) Error() string {
+	return e.Message
+}
+
+// returned when execute_function does not answer within the execution timeout. The outcome fields are
+// filled from the app logs, the function keeps running on App Services after the client gives up
+type appFunctionTimeoutError struct {
+	FunctionName     string
+	ExecutionTimeout int64
+	Finished         bool
+	Completed        string
+	FunctionError    string
+	LogsError        error
+}
+
+func (e *appFunctionTimeoutError) Error() string {
+	message := fmt.Sprintf("app function %s did not respond within the execution timeout of %d seconds", e.FunctionName, e.ExecutionTimeout)
+	switch {
+	case e.LogsError != nil:
+		return fmt.Sprintf("%s. Whether it finished is unknown, it may still be running. The app logs could not be read: %s", message, e.LogsError)
+	case e.Finished && e.FunctionError != "":
+		return fmt.Sprintf("%s. The app logs show it finished with an error at %s: %s", message, e.Completed, e.FunctionError)
+	case e.Finished:
+		return fmt.Sprintf("%s. The app logs show it finished successfully at %s, but its result is not available. Increase execution_timeout to receive the result", message, e.Completed)
+	}
+	return fmt.Sprintf("%s. The app logs show no finished run yet, it may still be running", message)
+}
+
+// controls how many times a function is executed and which failures are retried. RetryOn holds
+// "timeout", "5xx" and "function_error"
+type appFunctionRetryPolicy struct {
+	MaxAttempts int64
+	RetryOn     []string
+	RetryDelay  time.Duration
+}
+
+func (p appFunctionRetryPolicy) retries(reason string) bool {
+	for _, v := range p.RetryOn {
+		if v == reason {
+			return true
+		}
 	}
+	return false
+}
+
+// executes a function like executeAppServicesFunctionByName, retrying failures allowed by policy. A timed out
+// run is only retried when the app logs show it finished with an error. A run that is still going, or whose
+// outcome is unknown, is never retried so a second copy of the function does not start while the first may still run
+func executeAppServicesFunctionWithRetry(bearer_token string, projectID string, appServicesAppID string, functionName string, functionArgs []json.RawMessage, executionTimeout int64, runAsUserID string, policy appFunctionRetryPolicy) (*appFunctionExecution, error) {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := int64(1); ; attempt++ {
+		started := time.Now().UTC()
+		execution, err := executeAppServicesFunctionByName(bearer_token, projectID, appServicesAppID, functionName, functionArgs, executionTimeout, runAsUserID)
+
+		reason := ""
+		var timeoutErr *appFunctionTimeoutError
+		var statusErr *appFunctionStatusError
+		switch {
+		case errors.As(err, &timeoutErr):
+			completeAppFunctionTimeoutError(bearer_token, projectID, appServicesAppID, started, timeoutErr)
+			if timeoutErr.Finished && timeoutErr.FunctionError != "" {
+				reason = "timeout"
+			}
+		case errors.As(err, &statusErr):
+			if statusErr.StatusCode >= 500 {
+				reason = "5xx"
+			}
+		case err == nil && execution.Error != "":
+			reason = "function_error"
+		}
+
+		if reason == "" || attempt >= maxAttempts || !policy.retries(reason) {
+			if err != nil && attempt > 1 {
+				return nil, fmt.Errorf("%w (after %d attempts)", err, attempt)
+			}
+			if execution != nil {
+				execution.Attempts = attempt
+			}
+			return execution, err
+		}
+		time.Sleep(policy.RetryDelay)
+	}
+}
+
+// fills in whether a timed out function run finished, using the function logs written since started
+func completeAppFunctionTimeoutError(bearer_token string, projectID string, appServicesAppID string, started time.Time, timeoutErr *appFunctionTimeoutError) {
+	logs, err := getAppServicesFunctionLogs(bearer_token, projectID, appServicesAppID, started)
+	if err != nil {
+		timeoutErr.LogsError = err
+		return
+	}
+	for _, entry := range logs {
+		if name, _ := entry["function_name"].(string); name != timeoutErr.FunctionName {
+			continue
+		}
+		timeoutErr.Finished = true
+		timeoutErr.Completed, _ = entry["completed"].(string)
+		timeoutErr.FunctionError = appFunctionErrorMessage(entry["error"])
+		return
+	}
+}
+
+// lists function log entries written since startDate, newest first
+func getAppServicesFunctionLogs(bearer_token string, projectID string, appServicesAppID string, startDate time.Time) ([]map[string]interface{}, error) {
+	query := url.Values{}
+	query.Set("type", "FUNCTION")
+	query.Set("start_date", startDate.Format(time.RFC3339))
+	r, err := httpRequestWithBearerAuth(bearer_token, "GET", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/logs?%s", projectID, appServicesAppID, query.Encode()), "", 10)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to get app logs. Got statuscode: %d", r.StatusCode)
+	}
+
+	respjson, err := responseToMap(r)
+	if err != nil {
+		return nil, err
+	}
+	logs := []map[string]interface{}{}
+	if entries, ok := respjson["logs"].([]interface{}); ok {
+		for _, v := range entries {
+			if entry, ok := v.(map[string]interface{}); ok {
+				logs = append(logs, entry)
+			}
+		}
+	}
+	return logs, nil
+}
+
+// evaluates function source without deploying it by calling the exported function with functionArgs
+func executeAppServicesFunctionSource(bearer_token string, projectID string, appServicesAppID string, functionCode string, functionArgs []json.RawMessage, executionTimeout int64) (*appFunctionExecution, error) {
+	evalArgs := make([]string, 0, len(functionArgs))
+	for _, v := range functionArgs {
+		evalArgs = append(evalArgs, string(v))
+	}
+	jsonBytes, err := json.Marshal(map[string]interface{}{
+		"source":      functionCode,
+		"eval_source": fmt.Sprintf("exports(%s)", strings.Join(evalArgs, ", ")),
+	})
+	if err != nil {
+		return nil, err
+	}
+	jsonStr := string(jsonBytes)
+
+	if executionTimeout == 0 {
+		executionTimeout = 10
+	}
+
+	r, err := httpRequestWithBearerAuth(bearer_token, "POST", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/debug/execute_function_source?run_as_system=true", projectID, appServicesAppID), jsonStr, executionTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	execution, parseErr := responseToAppFunctionExecution(r)
+	if r.StatusCode == http.StatusOK && parseErr == nil {
+		return execution, nil
+	}
+	if parseErr == nil && execution.Error != "" {
+		return execution, nil
+	}
+	return nil, fmt.Errorf("unable to evaluate app function source. Got statuscode: %d", r.StatusCode)
+}
+
+func responseToAppFunctionExecution(r *http.Response) (*appFunctionExecution, error) {
+	respjson, err := responseToMap(r)
+	if err != nil {
+		return nil, err
+	}
+
+	execution := &appFunctionExecution{Logs: []string{}}
+	resultBytes, err := json.Marshal(respjson["result"])
+	if err != nil {
+		return nil, err
+	}
+	execution.ResultJSON = string(resultBytes)
+
+	if logs, ok := respjson["logs"].([]interface{}); ok {
+		for _, v := range logs {
+			if line, ok := v.(string); ok {
+				execution.Logs = append(execution.Logs, line)
+			} else {
+				lineBytes, _ := json.Marshal(v)
+				execution.Logs = append(execution.Logs, string(lineBytes))
+			}
+		}
+	}
+
+	if errorCode, ok := respjson["error_code"].(string); ok {
+		execution.ErrorCode = errorCode
+	}
+	execution.Error = appFunctionErrorMessage(respjson["error"])
+	if execution.Error == "" {
+		// functions commonly signal failure by returning {error: ...} instead of throwing
+		if result, ok := respjson["result"].(map[string]interface{}); ok {
+			execution.Error = appFunctionErrorMessage(result["error"])
+		}
+	}
+	return execution, nil
+}
+
+// reports whether an execution failed because the calling user is not allowed to run the function or access the data it uses
+func (e *appFunctionExecution) isPermissionError() bool {
+	switch e.ErrorCode {
+	case "FunctionExecutionPermissionDenied", "NoMatchingRuleFound", "ReadPermissionDenied", "WritePermissionDenied", "InsertPermissionDenied", "DeletePermissionDenied", "PermissionDenied":
+		return true
+	}
+	message := strings.ToLower(e.Error)
+	return strings.Contains(message, "permission") || strings.Contains(message, "no matching rule") || strings.Contains(message, "not authorized")
+}
+
+func appFunctionErrorMessage(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		if !v {
+			return ""
+		}
+	case string:
+		return v
+	case map[string]interface{}:
+		if message, ok := v["message"].(string); ok {
+			return message
+		}
+	}
+	errorBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(errorBytes)
 }
 
 func createAppServicesFunction(bearer_token string, projectID string, appServicesAppID string, functionName string, functionCode string) (string, error) {
-	// normalize function code
-	functionCode = strings.Replace(functionCode, "\n", "\\n", -1)
-	functionCode = strings.Replace(functionCode, "\t", "\\t", -1)
-	var jsonStr = fmt.Sprintf(`
-		{
-			"name": "%s",
-			"private": false,
-			"source": "%s",
-			"run_as_system": true
-	  	}
-	`, functionName, functionCode)
+	jsonBytes, err := json.Marshal(map[string]interface{}{
+		"name":          functionName,
+		"private":       false,
+		"source":        functionCode,
+		"run_as_system": true,
+	})
+	if err != nil {
+		return "", err
+	}
+	jsonStr := string(jsonBytes)
 
 	r, err := httpRequestWithBearerAuth(bearer_token, "POST", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/functions", projectID, appServicesAppID), jsonStr, 10)
 	if err != nil {
@@ -228,6 +576,29 @@ func createAppServicesFunction(bearer_token string, projectID string, appService
 	}
 }
 
+func updateAppServicesFunction(bearer_token string, projectID string, appServicesAppID string, functionID string, functionName string, functionCode string) error {
+	jsonBytes, err := json.Marshal(map[string]interface{}{
+		"name":          functionName,
+		"private":       false,
+		"source":        functionCode,
+		"run_as_system": true,
+	})
+	if err != nil {
+		return err
+	}
+	jsonStr := string(jsonBytes)
+
+	r, err := httpRequestWithBearerAuth(bearer_token, "PUT", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/functions/%s", projectID, appServicesAppID, functionID), jsonStr, 10)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	if r.StatusCode == http.StatusNoContent || r.StatusCode == http.StatusOK {
+		return nil
+	}
+	return fmt.Errorf("unable to update app function %s. Got statuscode: %d. Using json: %s", functionName, r.StatusCode, jsonStr)
+}
+
 func deleteAppServicesFunction(bearer_token string, projectID string, appServicesAppID string, functionID string) error {
 	r, err := httpRequestWithBearerAuth(bearer_token, "DELETE", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/functions/%s", projectID, appServicesAppID, functionID), "", 10)
 	if err != nil {
@@ -242,41 +613,149 @@ func deleteAppServicesFunction(bearer_token string, projectID string, appService
 
 // APP SERVICES FUNCTION DEPENDENCY
 
-func getAppFunctionDependencies(bearer_token string, projectID string, appServicesAppID string) ([]types.String, error) {
+// npm package installed for the functions of an app
+type appFunctionDependency struct {
+	Name    string
+	Version string
+}
+
+func getAppFunctionDependencies(bearer_token string, projectID string, appServicesAppID string) ([]appFunctionDependency, error) {
+	dependencies, _, err := getAppFunctionDependencyTree(bearer_token, projectID, appServicesAppID)
+	return dependencies, err
+}
+
+// a package in the resolved dependency tree with the names of the packages that require it, empty for
+// top-level packages
+type appFunctionResolvedPackage struct {
+	Name       string
+	Version    string
+	RequiredBy []string
+}
+
+// returns the top-level packages and every resolved package, including transitive ones, sorted by name and version.
+// The resolved packages are nil when the response does not include the dependency tree
+func getAppFunctionDependencyTree(bearer_token string, projectID string, appServicesAppID string) ([]appFunctionDependency, []appFunctionResolvedPackage, error) {
 	r, err := httpRequestWithBearerAuth(bearer_token, "GET", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/dependencies", projectID, appServicesAppID), "", 10)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unable to get app function dependencies. Got statuscode: %d", r.StatusCode)
+	}
 	respjson, err := responseToMap(r)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	dependencies, packages := appFunctionDependencyTreeFromResponse(respjson)
+	return dependencies, packages, nil
+}
+
+// reads the dependencies_list of a /dependencies response. Each entry is a {name, version, dependencies} object
+// whose dependencies list holds the packages it requires in the same shape. The tree is only considered present
+// when the entries carry a dependencies field, otherwise the resolved packages are nil rather than just the
+// top-level packages
+func appFunctionDependencyTreeFromResponse(respjson map[string]interface{}) ([]appFunctionDependency, []appFunctionResolvedPackage) {
+	dependenciesList, _ := respjson["dependencies_list"].([]interface{})
+	dependencies := make([]appFunctionDependency, 0, len(dependenciesList))
+	resolved := map[string]*appFunctionResolvedPackage{}
+	treeIncluded := len(dependenciesList) == 0
+	for _, v := range dependenciesList {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := entry["name"].(string)
+		version, _ := entry["version"].(string)
+		dependencies = append(dependencies, appFunctionDependency{Name: name, Version: version})
+		if _, ok := entry["dependencies"].([]interface{}); ok {
+			treeIncluded = true
+		}
+		collectAppFunctionResolvedPackages(resolved, "", name, entry)
+	}
+	if !treeIncluded {
+		return dependencies, nil
 	}
 
-	elements := make([]types.String, 0, len(respjson["dependencies_list"].([]interface{})))
-	for i := 0; i < len(respjson["dependencies_list"].([]interface{})); i++ {
-		elements = append(elements, basetypes.NewStringValue(respjson["dependencies_list"].([]interface{})[i].(map[string]interface{})["name"].(string)+" "+respjson["dependencies_list"].([]interface{})[i].(map[string]interface{})["version"].(string)))
+	packages := make([]appFunctionResolvedPackage, 0, len(resolved))
+	for _, v := range resolved {
+		sort.Strings(v.RequiredBy)
+		packages = append(packages, *v)
 	}
-	return elements, err
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+		return compareNpmVersions(packages[i].Version, packages[j].Version) < 0
+	})
+	return dependencies, packages
 }
 
-func getAppFunctionDependenciesStatus(bearer_token string, projectID string, appServicesAppID string) (string, string) {
+// compares versions by semver precedence, versions that do not parse sort after valid ones and by string
+func compareNpmVersions(a string, b string) int {
+	versionA, errA := semver.NewVersion(a)
+	versionB, errB := semver.NewVersion(b)
+	switch {
+	case errA == nil && errB == nil:
+		return versionA.Compare(versionB)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// adds the package described by entry and the packages of its dependencies list to resolved
+func collectAppFunctionResolvedPackages(resolved map[string]*appFunctionResolvedPackage, parent string, name string, entry map[string]interface{}) {
+	version, _ := entry["version"].(string)
+	key := name + "@" + version
+	resolvedPackage, seen := resolved[key]
+	if !seen {
+		resolvedPackage = &appFunctionResolvedPackage{Name: name, Version: version, RequiredBy: []string{}}
+		resolved[key] = resolvedPackage
+	}
+	if parent != "" && !slices.Contains(resolvedPackage.RequiredBy, parent) {
+		resolvedPackage.RequiredBy = append(resolvedPackage.RequiredBy, parent)
+	}
+	// shared packages are walked once, which also stops on cycles
+	if seen {
+		return
+	}
+
+	nested, _ := entry["dependencies"].([]interface{})
+	for _, v := range nested {
+		if child, ok := v.(map[string]interface{}); ok {
+			childName, _ := child["name"].(string)
+			collectAppFunctionResolvedPackages(resolved, name, childName, child)
+		}
+	}
+}
+
+func getAppFunctionDependenciesStatus(bearer_token string, projectID string, appServicesAppID string) (string, string, error) {
 	r, err := httpRequestWithBearerAuth(bearer_token, "GET", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/dependencies/status", projectID, appServicesAppID), "", 10)
 	if err != nil {
-		return "", ""
+		return "", "", err
 	}
 	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("unable to get app function dependencies status. Got statuscode: %d", r.StatusCode)
+	}
 	respjson, err := responseToMap(r)
 	if err != nil {
-		return "", ""
+		return "", "", err
 	}
-	return respjson["status"].(string), respjson["status_message"].(string)
+	status, ok := respjson["status"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("app function dependencies status response has no status")
+	}
+	status_message, _ := respjson["status_message"].(string)
+	return status, status_message, nil
 }
 
-func createAppFunctionDependencies(bearer_token string, projectID string, appServicesAppID string, dependencies []basetypes.StringValue) error {
-	for i := 0; i < len(dependencies); i++ {
-		depTokens := strings.Split(dependencies[i].ValueString(), " ")
-		err := manageAppFunctionDependency(bearer_token, projectID, appServicesAppID, depTokens[0], depTokens[1], "PUT")
+func createAppFunctionDependencies(ctx context.Context, bearer_token string, projectID string, appServicesAppID string, dependencies []appFunctionDependency) error {
+	for _, dependency := range dependencies {
+		err := manageAppFunctionDependency(ctx, bearer_token, projectID, appServicesAppID, dependency.Name, dependency.Version, "PUT")
 		if err != nil {
 			return err
 		}
@@ -284,43 +763,92 @@ func createAppFunctionDependencies(bearer_token string, projectID string, appSer
 	return nil
 }
 
-func manageAppFunctionDependency(bearer_token string, projectID string, appServicesAppID string, dependency string, version string, http_method string) error {
-	r, err := httpRequestWithBearerAuth(bearer_token, http_method, fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/dependencies/%s?version=%s", projectID, appServicesAppID, url.QueryEscape(dependency), version), "", 10)
+func manageAppFunctionDependency(ctx context.Context, bearer_token string, projectID string, appServicesAppID string, dependency string, version string, http_method string) error {
+	r, err := httpRequestWithBearerAuth(bearer_token, http_method, fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/dependencies/%s?version=%s", projectID, appServicesAppID, url.PathEscape(dependency), url.QueryEscape(version)), "", 10)
 	if err != nil {
 		return err
 	}
 	defer r.Body.Close()
-	if r.StatusCode == http.StatusNoContent {
-		tries := 0
-		for ok := true; ok; /*ok = ok*/ {
-			status, status_message := getAppFunctionDependenciesStatus(bearer_token, projectID, appServicesAppID)
-			tries = tries + 1
-			if status != "successful" {
-				if status == "failed" {
-					return fmt.Errorf("managing dependency %s : %s failed: %s (%s)", dependency, version, status_message, http_method)
-				}
-				time.Sleep(5 * time.Second)
-				if tries >= 240 {
-					return fmt.Errorf("exceeded max number of tries for successfully managing dependency %s : %s (%s)", dependency, version, http_method)
-				}
-			} else {
-				break
+	if r.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unable to manage app function dependency %s : %s (%s). Got statuscode: %d", dependency, version, http_method, r.StatusCode)
+	}
+	return waitForAppFunctionDependenciesBuild(ctx, bearer_token, projectID, appServicesAppID, fmt.Sprintf("managing dependency %s : %s (%s)", dependency, version, http_method))
+}
+
+// uploads a zip or tar archive holding node_modules or a package.json. App Services installs the whole set in a single build
+func uploadAppFunctionDependencyArchive(ctx context.Context, bearer_token string, projectID string, appServicesAppID string, fileName string, archive []byte) error {
+	r, err := httpMultipartRequestWithBearerAuth(bearer_token, "POST", fmt.Sprintf("https://services.cloud.mongodb.com/api/admin/v3.0/groups/%s/apps/%s/dependencies", projectID, appServicesAppID), "file", fileName, archive, 300)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusNoContent && r.StatusCode != http.StatusOK && r.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(r.Body)
+		return fmt.Errorf("unable to upload app function dependency archive %s. Got statuscode: %d. Response: %s", fileName, r.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return waitForAppFunctionDependenciesBuild(ctx, bearer_token, projectID, appServicesAppID, "uploading dependency archive "+fileName)
+}
+
+// waits until the dependency build started by the last change has finished, polling with exponential backoff
+// until ctx is done. Errors reading the status are returned immediately
+func waitForAppFunctionDependenciesBuild(ctx context.Context, bearer_token string, projectID string, appServicesAppID string, description string) error {
+	delay := 2 * time.Second
+	for {
+		status, status_message, err := getAppFunctionDependenciesStatus(bearer_token, projectID, appServicesAppID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", description, err)
+		}
+		switch status {
+		case "successful":
+			return nil
+		case "failed":
+			if status_message == "" {
+				status_message = "no status message was returned"
 			}
+			return fmt.Errorf("%s failed: %s", description, status_message)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out %s, last build status was %s", description, status)
+		case <-time.After(delay):
+		}
+		delay = delay * 2
+		if delay > 30*time.Second {
+			delay = 30 * time.Second
 		}
-	} else {
-		return fmt.Errorf("unable to manage app function dependency %s : %s (%s). Got statuscode: %d", dependency, version, http_method, r.StatusCode)
 	}
-	return nil
 }
 
-func deleteAllAppFunctionDependencies(bearer_token string, projectID string, appServicesAppID string) error {
+func deleteAllAppFunctionDependencies(ctx context.Context, bearer_token string, projectID string, appServicesAppID string) error {
 	dependencies, err := getAppFunctionDependencies(bearer_token, projectID, appServicesAppID)
 	if err != nil {
 		return fmt.Errorf("unable to get app function dependencies for subsequent deletion. got error: " + err.Error())
 	}
-	for i := 0; i < len(dependencies); i++ {
-		depTokens := strings.Split(dependencies[i].ValueString(), " ")
-		err := manageAppFunctionDependency(bearer_token, projectID, appServicesAppID, depTokens[0], depTokens[1], "DELETE")
+	for _, dependency := range dependencies {
+		err := manageAppFunctionDependency(ctx, bearer_token, projectID, appServicesAppID, dependency.Name, dependency.Version, "DELETE")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removes the given packages by name, skipping any that are no longer installed
+func deleteAppFunctionDependencies(ctx context.Context, bearer_token string, projectID string, appServicesAppID string, dependencies []appFunctionDependency) error {
+	installed, err := getAppFunctionDependencies(bearer_token, projectID, appServicesAppID)
+	if err != nil {
+		return fmt.Errorf("unable to get app function dependencies for subsequent deletion. got error: " + err.Error())
+	}
+	names := make(map[string]bool, len(dependencies))
+	for _, dependency := range dependencies {
+		names[dependency.Name] = true
+	}
+	for _, dependency := range installed {
+		if !names[dependency.Name] {
+			continue
+		}
+		err := manageAppFunctionDependency(ctx, bearer_token, projectID, appServicesAppID, dependency.Name, dependency.Version, "DELETE")
 		if err != nil {
 			return err
 		}
@@ -329,28 +857,327 @@ func deleteAllAppFunctionDependencies(bearer_token string, projectID string, app
 }
 
 // ATLAS CLUSTER CONTAINER
-func getClusterContainers(pubkey string, privkey string, projectID string, providerName string) (map[string]string, map[string]string, error) {
-	cidrs := make(map[string]string)
-	ids := make(map[string]string)
+// an Atlas network peering container. AWS and Azure containers belong to a single region, GCP containers are
+// global and list the regions they serve, if any
+type clusterContainer struct {
+	ID                  string
+	ProviderName        string
+	AtlasCIDRBlock      string
+	Provisioned         bool
+	Regions             []string
+	VpcID               string
+	AzureSubscriptionID string
+	VnetName            string
+	GCPProjectID        string
+	NetworkName         string
+}
+
+// reports whether the container serves region, given in Atlas or provider spelling. Global GCP containers
+// without regions are only matched by GLOBAL, so a misspelled region does not select them
+func (c clusterContainer) servesRegion(region string) bool {
+	if len(c.Regions) == 0 {
+		return strings.EqualFold(strings.TrimSpace(region), "GLOBAL")
+	}
+	return slices.Contains(c.Regions, normalizeAtlasRegion(c.ProviderName, region))
+}
+
+// returns the provider:region keys of the container. A GCP container without regions is keyed as GCP:GLOBAL
+func (c clusterContainer) keys() []string {
+	if len(c.Regions) == 0 {
+		return []string{c.ProviderName + ":GLOBAL"}
+	}
+	keys := make([]string, 0, len(c.Regions))
+	for _, region := range c.Regions {
+		keys = append(keys, c.ProviderName+":"+region)
+	}
+	return keys
+}
+
+func getClusterContainers(pubkey string, privkey string, projectID string, providerName string) ([]clusterContainer, error) {
 	url := fmt.Sprintf("https://cloud.mongodb.com/api/atlas/v2/groups/%s/containers?providerName=%s", projectID, providerName)
 	response, err := digestRequest("GET", url, pubkey, privkey, []byte(""), "application/vnd.atlas.2023-01-01+json")
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	ncontainers := len(response["results"].([]interface{}))
-	for i := 0; i < ncontainers; i++ {
-		container := response["results"].([]interface{})[i].(map[string]interface{})
-		regionNormalized := "ERR"
-		if providerName == "AWS" {
-			regionNormalized = container["regionName"].(string)
-		} else if providerName == "AZURE" {
-			regionNormalized = container["region"].(string)
-		} else {
-			return nil, nil, fmt.Errorf("%s not supported provider", providerName)
+	results, _ := response["results"].([]interface{})
+	containers := make([]clusterContainer, 0, len(results))
+	for _, v := range results {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		container, err := clusterContainerFromMap(entry)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+func getClusterContainer(pubkey string, privkey string, projectID string, containerID string) (clusterContainer, error) {
+	url := fmt.Sprintf("https://cloud.mongodb.com/api/atlas/v2/groups/%s/containers/%s", projectID, containerID)
+	response, err := digestRequest("GET", url, pubkey, privkey, []byte(""), "application/vnd.atlas.2023-01-01+json")
+	if err != nil {
+		return clusterContainer{}, err
+	}
+	return clusterContainerFromMap(response)
+}
+
+// creates the container when containerID is empty, otherwise updates its CIDR block and GCP regions
+func saveClusterContainer(pubkey string, privkey string, projectID string, containerID string, container clusterContainer) (clusterContainer, error) {
+	body := map[string]interface{}{
+		"providerName":   container.ProviderName,
+		"atlasCidrBlock": container.AtlasCIDRBlock,
+	}
+	switch container.ProviderName {
+	case "AWS":
+		body["regionName"] = container.Regions[0]
+	case "AZURE":
+		body["region"] = container.Regions[0]
+	case "GCP":
+		if container.Regions != nil {
+			body["regions"] = container.Regions
+		}
+	}
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return clusterContainer{}, err
+	}
+
+	http_method := "POST"
+	url := fmt.Sprintf("https://cloud.mongodb.com/api/atlas/v2/groups/%s/containers", projectID)
+	if containerID != "" {
+		http_method = "PATCH"
+		url += "/" + containerID
+	}
+	response, err := digestRequest(http_method, url, pubkey, privkey, jsonBody, "application/vnd.atlas.2023-01-01+json")
+	if err != nil {
+		return clusterContainer{}, err
+	}
+	return clusterContainerFromMap(response)
+}
+
+func deleteClusterContainer(pubkey string, privkey string, projectID string, containerID string) error {
+	url := fmt.Sprintf("https://cloud.mongodb.com/api/atlas/v2/groups/%s/containers/%s", projectID, containerID)
+	_, err := digestRequest("DELETE", url, pubkey, privkey, []byte(""), "application/vnd.atlas.2023-01-01+json")
+	return err
+}
+
+func clusterContainerFromMap(entry map[string]interface{}) (clusterContainer, error) {
+	container := clusterContainer{}
+	container.ID, _ = entry["id"].(string)
+	container.ProviderName, _ = entry["providerName"].(string)
+	container.AtlasCIDRBlock, _ = entry["atlasCidrBlock"].(string)
+	container.Provisioned, _ = entry["provisioned"].(bool)
+	switch container.ProviderName {
+	case "AWS":
+		region, _ := entry["regionName"].(string)
+		container.Regions = []string{region}
+		container.VpcID, _ = entry["vpcId"].(string)
+	case "AZURE":
+		region, _ := entry["region"].(string)
+		container.Regions = []string{normalizeAtlasRegion("AZURE", region)}
+		container.AzureSubscriptionID, _ = entry["azureSubscriptionId"].(string)
+		container.VnetName, _ = entry["vnetName"].(string)
+	case "GCP":
+		container.GCPProjectID, _ = entry["gcpProjectId"].(string)
+		container.NetworkName, _ = entry["networkName"].(string)
+		regions, _ := entry["regions"].([]interface{})
+		container.Regions = make([]string, 0, len(regions))
+		for _, region := range regions {
+			if name, ok := region.(string); ok {
+				container.Regions = append(container.Regions, name)
+			}
+		}
+	default:
+		return clusterContainer{}, fmt.Errorf("%s not supported provider", container.ProviderName)
+	}
+	return container, nil
+}
+
+// an Atlas network peering connection. Which fields are set depends on ProviderName
+type networkPeering struct {
+	ID                  string
+	ContainerID         string
+	ProviderName        string
+	Status              string
+	ErrorMessage        string
+	AccepterRegionName  string
+	AWSAccountID        string
+	RouteTableCIDRBlock string
+	VpcID               string
+	ConnectionID        string
+	AzureDirectoryID    string
+	AzureSubscriptionID string
+	ResourceGroupName   string
+	VnetName            string
+	GCPProjectID        string
+	NetworkName         string
+}
+
+// peering statuses that only change through action outside Atlas, or not at all. PENDING_ACCEPTANCE and
+// WAITING_FOR_USER wait on the peer VPC or VNet owner
+var networkPeeringTerminalStatuses = []string{"AVAILABLE", "FAILED", "PENDING_ACCEPTANCE", "WAITING_FOR_USER"}
+
+func listNetworkPeerings(pubkey string, privkey string, projectID string, providerName string) ([]networkPeering, error) {
+	url := fmt.Sprintf("https://cloud.mongodb.com/api/atlas/v2/groups/%s/peers?providerName=%s&itemsPerPage=500", projectID, providerName)
+	response, err := digestRequest("GET", url, pubkey, privkey, []byte(""), "application/vnd.atlas.2023-01-01+json")
+	if err != nil {
+		return nil, err
+	}
+	results, _ := response["results"].([]interface{})
+	peerings := make([]networkPeering, 0, len(results))
+	for _, v := range results {
+		if entry, ok := v.(map[string]interface{}); ok {
+			peerings = append(peerings, networkPeeringFromMap(entry, providerName))
+		}
+	}
+	return peerings, nil
+}
+
+func getNetworkPeering(pubkey string, privkey string, projectID string, peerID string, providerName string) (networkPeering, error) {
+	url := fmt.Sprintf("https://cloud.mongodb.com/api/atlas/v2/groups/%s/peers/%s", projectID, peerID)
+	response, err := digestRequest("GET", url, pubkey, privkey, []byte(""), "application/vnd.atlas.2023-01-01+json")
+	if err != nil {
+		return networkPeering{}, err
+	}
+	return networkPeeringFromMap(response, providerName), nil
+}
+
+// creates the peering connection when peerID is empty, otherwise updates it
+func saveNetworkPeering(pubkey string, privkey string, projectID string, peerID string, peering networkPeering) (networkPeering, error) {
+	body := map[string]interface{}{
+		"containerId":  peering.ContainerID,
+		"providerName": peering.ProviderName,
+	}
+	switch peering.ProviderName {
+	case "AWS":
+		body["accepterRegionName"] = peering.AccepterRegionName
+		body["awsAccountId"] = peering.AWSAccountID
+		body["routeTableCidrBlock"] = peering.RouteTableCIDRBlock
+		body["vpcId"] = peering.VpcID
+	case "AZURE":
+		body["azureDirectoryId"] = peering.AzureDirectoryID
+		body["azureSubscriptionId"] = peering.AzureSubscriptionID
+		body["resourceGroupName"] = peering.ResourceGroupName
+		body["vnetName"] = peering.VnetName
+	case "GCP":
+		body["gcpProjectId"] = peering.GCPProjectID
+		body["networkName"] = peering.NetworkName
+	}
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return networkPeering{}, err
+	}
+
+	http_method := "POST"
+	url := fmt.Sprintf("https://cloud.mongodb.com/api/atlas/v2/groups/%s/peers", projectID)
+	if peerID != "" {
+		http_method = "PATCH"
+		url += "/" + peerID
+	}
+	response, err := digestRequest(http_method, url, pubkey, privkey, jsonBody, "application/vnd.atlas.2023-01-01+json")
+	if err != nil {
+		return networkPeering{}, err
+	}
+	return networkPeeringFromMap(response, peering.ProviderName), nil
+}
+
+func deleteNetworkPeering(pubkey string, privkey string, projectID string, peerID string) error {
+	url := fmt.Sprintf("https://cloud.mongodb.com/api/atlas/v2/groups/%s/peers/%s", projectID, peerID)
+	_, err := digestRequest("DELETE", url, pubkey, privkey, []byte(""), "application/vnd.atlas.2023-01-01+json")
+	return err
+}
+
+// waits until the peering connection reaches a terminal status, polling with exponential backoff until ctx is done
+func waitForNetworkPeering(ctx context.Context, pubkey string, privkey string, projectID string, peerID string, providerName string) (networkPeering, error) {
+	delay := 5 * time.Second
+	for {
+		peering, err := getNetworkPeering(pubkey, privkey, projectID, peerID, providerName)
+		if err != nil {
+			return networkPeering{}, err
+		}
+		if slices.Contains(networkPeeringTerminalStatuses, peering.Status) {
+			return peering, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return peering, fmt.Errorf("timed out waiting for network peering %s, last status was %s", peerID, peering.Status)
+		case <-time.After(delay):
+		}
+		delay = delay * 2
+		if delay > 30*time.Second {
+			delay = 30 * time.Second
+		}
+	}
+}
+
+// waits until Atlas no longer returns the peering connection, polling with exponential backoff until ctx is done
+func waitForNetworkPeeringDeleted(ctx context.Context, pubkey string, privkey string, projectID string, peerID string, providerName string) error {
+	delay := 5 * time.Second
+	for {
+		peering, err := getNetworkPeering(pubkey, privkey, projectID, peerID, providerName)
+		if isDigestNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for network peering %s to be deleted, last status was %s", peerID, peering.Status)
+		case <-time.After(delay):
+		}
+		delay = delay * 2
+		if delay > 30*time.Second {
+			delay = 30 * time.Second
+		}
+	}
+}
+
+// AWS peers report statusName and errorStateName, Azure peers status and errorState and GCP peers status and
+// errorMessage. Not every response names the provider, providerName or the provider specific fields are used then
+func networkPeeringFromMap(entry map[string]interface{}, providerName string) networkPeering {
+	peering := networkPeering{}
+	peering.ID, _ = entry["id"].(string)
+	peering.ContainerID, _ = entry["containerId"].(string)
+	peering.ProviderName, _ = entry["providerName"].(string)
+	if peering.ProviderName == "" {
+		peering.ProviderName = providerName
+	}
+	peering.Status, _ = entry["statusName"].(string)
+	if peering.Status == "" {
+		peering.Status, _ = entry["status"].(string)
+	}
+	for _, key := range []string{"errorStateName", "errorState", "errorMessage"} {
+		if message, ok := entry[key].(string); ok && message != "" {
+			peering.ErrorMessage = message
+			break
+		}
+	}
+	peering.AccepterRegionName, _ = entry["accepterRegionName"].(string)
+	peering.AWSAccountID, _ = entry["awsAccountId"].(string)
+	peering.RouteTableCIDRBlock, _ = entry["routeTableCidrBlock"].(string)
+	peering.VpcID, _ = entry["vpcId"].(string)
+	peering.ConnectionID, _ = entry["connectionId"].(string)
+	peering.AzureDirectoryID, _ = entry["azureDirectoryId"].(string)
+	peering.AzureSubscriptionID, _ = entry["azureSubscriptionId"].(string)
+	peering.ResourceGroupName, _ = entry["resourceGroupName"].(string)
+	peering.VnetName, _ = entry["vnetName"].(string)
+	peering.GCPProjectID, _ = entry["gcpProjectId"].(string)
+	peering.NetworkName, _ = entry["networkName"].(string)
+	if peering.ProviderName == "" {
+		switch {
+		case peering.AWSAccountID != "":
+			peering.ProviderName = "AWS"
+		case peering.AzureDirectoryID != "":
+			peering.ProviderName = "AZURE"
+		case peering.GCPProjectID != "":
+			peering.ProviderName = "GCP"
 		}
-		container_key := fmt.Sprintf("%s:%s", container["providerName"], regionNormalized)
-		cidrs[container_key] = container["atlasCidrBlock"].(string)
-		ids[container_key] = container["id"].(string)
 	}
-	return ids, cidrs, err
+	return peering
 }
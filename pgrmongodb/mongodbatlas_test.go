@@ -0,0 +1,152 @@
+package pgrmongodb
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestAppFunctionArguments(t *testing.T) {
+	tests := []struct {
+		name             string
+		functionArgs     []string
+		functionArgsJSON []string
+		want             []string
+		wantErr          bool
+	}{
+		{name: "none", want: []string{}},
+		{name: "strings", functionArgs: []string{"a", `say "hi"`}, want: []string{`"a"`, `"say \"hi\""`}},
+		{name: "json keeps order and duplicates", functionArgsJSON: []string{"42", `{"dryRun":true}`, "42"}, want: []string{"42", `{"dryRun":true}`, "42"}},
+		{name: "invalid json", functionArgsJSON: []string{"{not json"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arguments, err := appFunctionArguments(tt.functionArgs, tt.functionArgsJSON)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := make([]string, 0, len(arguments))
+			for _, v := range arguments {
+				got = append(got, string(v))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppFunctionArgumentsFromArray(t *testing.T) {
+	tests := []struct {
+		name      string
+		arrayJSON string
+		want      []string
+		wantErr   bool
+	}{
+		{name: "empty", arrayJSON: "[]", want: []string{}},
+		{name: "mixed", arrayJSON: `["tfarg1", 42, {"dryRun": true}, null]`, want: []string{`"tfarg1"`, "42", `{"dryRun": true}`, "null"}},
+		{name: "object", arrayJSON: `{"dryRun": true}`, wantErr: true},
+		{name: "null", arrayJSON: "null", wantErr: true},
+		{name: "invalid", arrayJSON: "[1,", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := appFunctionArgumentsFromArray(tt.arrayJSON)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			for _, v := range got {
+				if !json.Valid([]byte(v)) {
+					t.Errorf("element %s is not valid JSON", v)
+				}
+			}
+		})
+	}
+}
+
+func TestAppFunctionDependencyTreeFromResponse(t *testing.T) {
+	body, err := os.ReadFile("testdata/appfunctiondependencies.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var respjson map[string]interface{}
+	if err := json.Unmarshal(body, &respjson); err != nil {
+		t.Fatal(err)
+	}
+
+	dependencies, packages := appFunctionDependencyTreeFromResponse(respjson)
+	wantDependencies := []appFunctionDependency{
+		{Name: "axios", Version: "1.6.8"},
+		{Name: "lodash", Version: "4.17.21"},
+		{Name: "lodash-wrapper", Version: "1.0.0"},
+	}
+	if !reflect.DeepEqual(dependencies, wantDependencies) {
+		t.Errorf("got dependencies %v, want %v", dependencies, wantDependencies)
+	}
+	// lodash 4.9.0 sorts before 4.17.21 and mime-types is listed once with both parents
+	wantPackages := []appFunctionResolvedPackage{
+		{Name: "asynckit", Version: "0.4.0", RequiredBy: []string{"form-data"}},
+		{Name: "axios", Version: "1.6.8", RequiredBy: []string{}},
+		{Name: "combined-stream", Version: "1.0.8", RequiredBy: []string{"form-data"}},
+		{Name: "delayed-stream", Version: "1.0.0", RequiredBy: []string{"combined-stream"}},
+		{Name: "follow-redirects", Version: "1.15.6", RequiredBy: []string{"axios"}},
+		{Name: "form-data", Version: "4.0.0", RequiredBy: []string{"axios"}},
+		{Name: "lodash", Version: "4.9.0", RequiredBy: []string{"lodash-wrapper"}},
+		{Name: "lodash", Version: "4.17.21", RequiredBy: []string{}},
+		{Name: "lodash-wrapper", Version: "1.0.0", RequiredBy: []string{}},
+		{Name: "mime-types", Version: "2.1.35", RequiredBy: []string{"form-data", "lodash-wrapper"}},
+	}
+	if !reflect.DeepEqual(packages, wantPackages) {
+		t.Errorf("got packages %v, want %v", packages, wantPackages)
+	}
+}
+
+func TestAppFunctionDependencyTreeFromResponseWithoutTree(t *testing.T) {
+	var respjson map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"dependencies_list": [{"name": "lodash", "version": "4.17.21"}]}`), &respjson); err != nil {
+		t.Fatal(err)
+	}
+	dependencies, packages := appFunctionDependencyTreeFromResponse(respjson)
+	if len(dependencies) != 1 {
+		t.Errorf("got %d dependencies, want 1", len(dependencies))
+	}
+	if packages != nil {
+		t.Errorf("got packages %v, want nil when the response has no dependency tree", packages)
+	}
+
+	if err := json.Unmarshal([]byte(`{"dependencies_list": []}`), &respjson); err != nil {
+		t.Fatal(err)
+	}
+	if _, packages := appFunctionDependencyTreeFromResponse(respjson); packages == nil || len(packages) != 0 {
+		t.Errorf("got packages %v, want an empty list for an app without dependencies", packages)
+	}
+}
+
+func TestCompareNpmVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "4.9.0", b: "4.17.21", want: -1},
+		{a: "10.0.0", b: "9.1.0", want: 1},
+		{a: "1.0.0-beta.2", b: "1.0.0", want: -1},
+		{a: "1.0.0", b: "1.0.0", want: 0},
+		{a: "1.0.0", b: "not-a-version", want: -1},
+		{a: "b", b: "a", want: 1},
+	}
+	for _, tt := range tests {
+		if got := compareNpmVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareNpmVersions(%q, %q) is %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package pgrmongodb
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+const defaultNpmRegistryURL = "https://registry.npmjs.org"
+
+var (
+	exactNpmVersionRegex = regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+	npmDistTagRegex      = regexp.MustCompile(`^[A-Za-z][0-9A-Za-z._-]*$`)
+)
+
+// reports whether version is a pinned version rather than a range or dist-tag
+func isExactNpmVersion(version string) bool {
+	return exactNpmVersionRegex.MatchString(strings.TrimSpace(version))
+}
+
+// reports whether version is a pinned version, a semver range such as ^4.17.0 or a dist-tag such as latest
+func isValidNpmVersion(version string) bool {
+	if isExactNpmVersion(version) {
+		return true
+	}
+	if _, err := semver.NewConstraint(version); err == nil {
+		return true
+	}
+	return npmDistTagRegex.MatchString(version)
+}
+
+// resolves a semver range or dist-tag to the highest matching version published to registryURL. Pinned
+// versions are returned unchanged without querying the registry
+func resolveNpmVersion(registryURL string, name string, versionRange string) (string, error) {
+	versionRange = strings.TrimSpace(versionRange)
+	if isExactNpmVersion(versionRange) {
+		return strings.TrimPrefix(versionRange, "v"), nil
+	}
+
+	// the abbreviated metadata document only holds what install needs, which keeps large packages fast to fetch
+	packageURL := strings.TrimSuffix(registryURL, "/") + "/" + strings.Replace(url.PathEscape(name), "%40", "@", 1)
+	req, err := http.NewRequest("GET", packageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.npm.install-v1+json")
+	client := &http.Client{Timeout: 30 * time.Second}
+	r, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to get npm package %s from %s. Got statuscode: %d", name, registryURL, r.StatusCode)
+	}
+	respjson, err := responseToMap(r)
+	if err != nil {
+		return "", err
+	}
+
+	if distTags, ok := respjson["dist-tags"].(map[string]interface{}); ok {
+		if tagged, ok := distTags[versionRange].(string); ok {
+			return tagged, nil
+		}
+	}
+
+	constraint, err := semver.NewConstraint(versionRange)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a dist-tag of npm package %s or a valid semver range: %s", versionRange, name, err)
+	}
+	published, _ := respjson["versions"].(map[string]interface{})
+	versions := make([]*semver.Version, 0, len(published))
+	for v := range published {
+		if version, err := semver.NewVersion(v); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	sort.Sort(sort.Reverse(semver.Collection(versions)))
+	for _, version := range versions {
+		if constraint.Check(version) {
+			return version.Original(), nil
+		}
+	}
+	return "", fmt.Errorf("no published version of npm package %s matches %s", name, versionRange)
+}
@@ -6,9 +6,11 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"strings"
 	"time"
@@ -34,6 +36,33 @@ func httpRequestWithBearerAuth(token string, method string, url string, messageb
 	}
 }
 
+// sends content as a multipart/form-data file upload in fieldName
+func httpMultipartRequestWithBearerAuth(token string, method string, url string, fieldName string, fileName string, content []byte, http_timeout int64) (*http.Response, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(fieldName, fileName)
+	if err != nil {
+		return &http.Response{}, err
+	}
+	if _, err := part.Write(content); err != nil {
+		return &http.Response{}, err
+	}
+	if err := writer.Close(); err != nil {
+		return &http.Response{}, err
+	}
+
+	client := &http.Client{Timeout: time.Duration(http_timeout) * time.Second}
+	req, err := http.NewRequest(method, url, &body)
+	if err != nil {
+		return &http.Response{}, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return client.Do(req)
+}
+
 func responseToArrayOfMap(r *http.Response) ([]map[string]interface{}, error) {
 	_, resp, err := responseToMapHelper(r, true)
 	return resp, err
@@ -124,8 +153,29 @@ func digestRequest(http_method string, uri string, pubkey string, privkey string
 			return response, nil
 		}
 	} else {
-		return nil, fmt.Errorf("received status code %d but expected 200", r.StatusCode)
+		bodyBytes, _ := ioutil.ReadAll(r.Body)
+		return nil, &digestStatusError{StatusCode: r.StatusCode, Body: strings.TrimSpace(string(bodyBytes))}
+	}
+}
+
+// returned by digestRequest when the authenticated request answers with a non 2xx status code. Body holds
+// the Atlas error document, which names the reason such as CONTAINER_ALREADY_EXISTS
+type digestStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *digestStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("received status code %d but expected 200", e.StatusCode)
 	}
+	return fmt.Sprintf("received status code %d but expected 200. Response: %s", e.StatusCode, e.Body)
+}
+
+// reports whether err is a digestRequest error for a resource that does not exist
+func isDigestNotFound(err error) bool {
+	var statusErr *digestStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
 }
 
 func digestParts(resp *http.Response) map[string]string {
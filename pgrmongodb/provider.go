@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -18,7 +19,8 @@ import (
 )
 
 var (
-	_ provider.Provider = &pgrmongodb_provider{}
+	_ provider.Provider                       = &pgrmongodb_provider{}
+	_ provider.ProviderWithEphemeralResources = &pgrmongodb_provider{}
 )
 
 func New() provider.Provider {
@@ -28,14 +30,17 @@ func New() provider.Provider {
 type pgrmongodb_provider struct{}
 
 type pgrmongodbProviderModel struct {
-	PublicKey  types.String `tfsdk:"public_key"`
-	PrivateKey types.String `tfsdk:"private_key"`
+	PublicKey      types.String `tfsdk:"public_key"`
+	PrivateKey     types.String `tfsdk:"private_key"`
+	NpmRegistryURL types.String `tfsdk:"npm_registry_url"`
 }
 
 type providerData struct {
 	bearer_token string
 	public_key   string
 	private_key  string
+	// registry used to resolve npm version ranges of app function dependencies
+	npm_registry_url string
 }
 
 func (p *pgrmongodb_provider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -55,6 +60,10 @@ func (p *pgrmongodb_provider) Schema(_ context.Context, _ provider.SchemaRequest
 				Sensitive:   true,
 				Description: "MongoDB Atlas private API key.",
 			},
+			"npm_registry_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "npm registry used to resolve version ranges and dist-tags of app function dependencies. Defaults to the PGRMONGODB_NPM_REGISTRY_URL environment variable or https://registry.npmjs.org.",
+			},
 		},
 	}
 }
@@ -135,15 +144,34 @@ func (p *pgrmongodb_provider) Configure(ctx context.Context, req provider.Config
 	data.bearer_token = bearer_token
 	data.public_key = public_key
 	data.private_key = private_key
+	data.npm_registry_url = os.Getenv("PGRMONGODB_NPM_REGISTRY_URL")
+	if !config.NpmRegistryURL.IsNull() && !config.NpmRegistryURL.IsUnknown() {
+		data.npm_registry_url = config.NpmRegistryURL.ValueString()
+	}
+	if data.npm_registry_url == "" {
+		data.npm_registry_url = defaultNpmRegistryURL
+	}
 
 	resp.DataSourceData = data
 	resp.ResourceData = data
+	resp.EphemeralResourceData = data
 }
 
 func (p *pgrmongodb_provider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewAtlasClusterContainerDataSource,
 		NewAppFunctionExecuteDataSource,
+		NewAppFunctionDataSource,
+		NewAppFunctionsDataSource,
+		NewAppFunctionEvalDataSource,
+		NewAppFunctionDependenciesDataSource,
+		NewNetworkPeeringDataSource,
+	}
+}
+
+func (p *pgrmongodb_provider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewAppFunctionExecuteEphemeralResource,
 	}
 }
 
@@ -152,6 +180,10 @@ func (p *pgrmongodb_provider) Resources(_ context.Context) []func() resource.Res
 		NewAppServicesAppResource,
 		NewAppFunctionResource,
 		NewAppFunctionDependencies,
+		NewAppFunctionsResource,
+		NewAppFunctionInvocationResource,
+		NewAtlasClusterContainerResource,
+		NewNetworkPeeringResource,
 	}
 }
 
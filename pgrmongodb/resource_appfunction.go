@@ -2,6 +2,7 @@ package pgrmongodb
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -10,16 +11,20 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tdewolff/parse/v2"
 )
 
 var (
-	_ resource.Resource                = &appFunctionResource{}
-	_ resource.ResourceWithConfigure   = &appFunctionResource{}
-	_ resource.ResourceWithImportState = &appFunctionResource{}
+	_ resource.Resource                   = &appFunctionResource{}
+	_ resource.ResourceWithConfigure      = &appFunctionResource{}
+	_ resource.ResourceWithImportState    = &appFunctionResource{}
+	_ resource.ResourceWithValidateConfig = &appFunctionResource{}
+	_ resource.ResourceWithModifyPlan     = &appFunctionResource{}
 )
 
 func NewAppFunctionResource() resource.Resource {
@@ -36,6 +41,17 @@ type appFunctionResourceModel struct {
 	AppServicesAppID types.String `tfsdk:"appservices_app_id"`
 	FunctionName     types.String `tfsdk:"function_name"`
 	FunctionCode     types.String `tfsdk:"function_code"`
+	Entrypoint       types.String `tfsdk:"entrypoint"`
+	Language         types.String `tfsdk:"language"`
+	FunctionCodeHash types.String `tfsdk:"function_code_hash"`
+}
+
+// returns the javascript deployed to App Services for function_code or entrypoint
+func (m appFunctionResourceModel) deployedSource() (string, *parse.Error) {
+	if !m.Entrypoint.IsNull() {
+		return bundleAppFunctionEntrypoint(m.Entrypoint.ValueString())
+	}
+	return compileAppFunctionCode(m.FunctionCode.ValueString(), m.Language.ValueString())
 }
 
 func (r *appFunctionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -91,11 +107,31 @@ func (r *appFunctionResource) Schema(_ context.Context, _ resource.SchemaRequest
 				},
 			},
 			"function_code": schema.StringAttribute{
-				Description: "Code to be deployed to App Services function.",
-				Required:    true,
+				Description: "Code to be deployed to App Services function. Syntax is validated at plan time. Exactly one of function_code or entrypoint must be set.",
+				Optional:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("entrypoint")),
+				},
+			},
+			"entrypoint": schema.StringAttribute{
+				Description: "Path to a JavaScript or TypeScript file to deploy to App Services function. Local relative imports (./ and ../) are bundled into a single source, other modules such as packages installed with pgrmongodb_appfunctiondependencies stay external.",
+				Optional:    true,
+			},
+			"language": schema.StringAttribute{
+				Description: "Language of function_code, either javascript or typescript. TypeScript is transpiled to JavaScript by the provider before deployment. Defaults to javascript. Files bundled from entrypoint use their file extension instead.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("javascript"),
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"javascript", "typescript"}...),
+				},
+			},
+			"function_code_hash": schema.StringAttribute{
+				Description: "SHA256 hash of the JavaScript source deployed to App Services.",
+				Computed:    true,
 			},
 		},
 	}
@@ -109,6 +145,86 @@ func (r *appFunctionResource) Configure(_ context.Context, req resource.Configur
 	r.bearer_token = req.ProviderData.(providerData).bearer_token
 }
 
+func (r *appFunctionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config appFunctionResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.FunctionCode.IsUnknown() || config.Entrypoint.IsUnknown() || config.Language.IsUnknown() {
+		return
+	}
+	if config.FunctionCode.IsNull() == config.Entrypoint.IsNull() {
+		// reported by the function_code validators
+		return
+	}
+
+	sourceAttribute := path.Root("function_code")
+	if !config.Entrypoint.IsNull() {
+		sourceAttribute = path.Root("entrypoint")
+	}
+
+	deployedCode, parseErr := config.deployedSource()
+	if parseErr != nil {
+		detail := fmt.Sprintf("function_code contains a TypeScript compile error on line %d, column %d: %s\n\n%s", parseErr.Line, parseErr.Column, parseErr.Message, parseErr.Context)
+		if !config.Entrypoint.IsNull() {
+			detail = fmt.Sprintf("entrypoint could not be bundled, error on line %d, column %d: %s\n\n%s", parseErr.Line, parseErr.Column, parseErr.Message, parseErr.Context)
+		}
+		resp.Diagnostics.AddAttributeError(
+			sourceAttribute,
+			"Invalid App Services Function Code",
+			detail,
+		)
+		return
+	}
+
+	hasExports, parseErr := parseAppFunctionCode(deployedCode)
+	if parseErr != nil {
+		resp.Diagnostics.AddAttributeError(
+			sourceAttribute,
+			"Invalid App Services Function Code",
+			fmt.Sprintf("%s contains a JavaScript syntax error on line %d, column %d: %s\n\n%s", sourceAttribute.String(), parseErr.Line, parseErr.Column, parseErr.Message, parseErr.Context),
+		)
+		return
+	}
+	if !hasExports {
+		resp.Diagnostics.AddAttributeWarning(
+			sourceAttribute,
+			"App Services Function Code Does Not Assign exports",
+			sourceAttribute.String()+" never assigns exports. App Services calls the value assigned to exports (for example exports = async function() { ... }), so this function cannot be executed.",
+		)
+	}
+}
+
+func (r *appFunctionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan appFunctionResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.FunctionCode.IsUnknown() || plan.Entrypoint.IsUnknown() || plan.Language.IsUnknown() {
+		plan.FunctionCodeHash = types.StringUnknown()
+	} else {
+		// compile and bundle errors are reported by ValidateConfig
+		deployedCode, parseErr := plan.deployedSource()
+		if parseErr != nil {
+			return
+		}
+		plan.FunctionCodeHash = types.StringValue(hashAppFunctionCode(deployedCode))
+	}
+
+	diags = resp.Plan.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
 func (r *appFunctionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan appFunctionResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -120,7 +236,14 @@ func (r *appFunctionResource) Create(ctx context.Context, req resource.CreateReq
 	projectID := plan.ProjectID.ValueString()
 	appServicesAppID := plan.AppServicesAppID.ValueString()
 	functionName := plan.FunctionName.ValueString()
-	functionCode := plan.FunctionCode.ValueString()
+	functionCode, parseErr := plan.deployedSource()
+	if parseErr != nil {
+		resp.Diagnostics.AddError(
+			"Error Compiling App Services Function",
+			"Could not compile function source. Received error: "+parseErr.Error(),
+		)
+		return
+	}
 
 	tflog.Info(ctx, "creating mongodb atlas app services function")
 	function_id, err := createAppServicesFunction(r.bearer_token, projectID, appServicesAppID, functionName, functionCode)
@@ -133,6 +256,7 @@ func (r *appFunctionResource) Create(ctx context.Context, req resource.CreateReq
 	}
 
 	plan.ID = types.StringValue(function_id)
+	plan.FunctionCodeHash = types.StringValue(hashAppFunctionCode(functionCode))
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -164,7 +288,14 @@ func (r *appFunctionResource) Read(ctx context.Context, req resource.ReadRequest
 	}
 
 	state.ID = types.StringValue(function_id)
-	state.FunctionCode = types.StringValue(function_code)
+	if state.Language.IsNull() {
+		state.Language = types.StringValue("javascript")
+	}
+	// typescript and bundled sources can't be recovered from the deployed javascript, so drift is detected through the hash
+	if state.Language.ValueString() == "javascript" && state.Entrypoint.IsNull() {
+		state.FunctionCode = types.StringValue(function_code)
+	}
+	state.FunctionCodeHash = types.StringValue(hashAppFunctionCode(function_code))
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -189,14 +320,22 @@ func (r *appFunctionResource) Update(ctx context.Context, req resource.UpdateReq
 	}
 
 	hasChange := false
-	if state.FunctionName != plan.FunctionName || state.ProjectID != plan.ProjectID || state.FunctionCode != plan.FunctionCode {
+	if state.FunctionName != plan.FunctionName || state.ProjectID != plan.ProjectID || state.FunctionCode != plan.FunctionCode || state.Entrypoint != plan.Entrypoint || state.Language != plan.Language || state.FunctionCodeHash != plan.FunctionCodeHash {
 		hasChange = true
 	}
 
 	tflog.Info(ctx, "checking mongodb atlas app services function for deltas")
 	if hasChange {
 		tflog.Info(ctx, "updating mongodb atlas app services function")
-		function_id, err := createAppServicesFunction(r.bearer_token, plan.ProjectID.ValueString(), plan.AppServicesAppID.ValueString(), plan.FunctionName.ValueString(), plan.FunctionCode.ValueString())
+		functionCode, parseErr := plan.deployedSource()
+		if parseErr != nil {
+			resp.Diagnostics.AddError(
+				"Error Compiling App Services Function",
+				"Could not compile function source. Received error: "+parseErr.Error(),
+			)
+			return
+		}
+		function_id, err := createAppServicesFunction(r.bearer_token, plan.ProjectID.ValueString(), plan.AppServicesAppID.ValueString(), plan.FunctionName.ValueString(), functionCode)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error Creating App Services Function",
@@ -217,6 +356,9 @@ func (r *appFunctionResource) Update(ctx context.Context, req resource.UpdateReq
 		state.ProjectID = plan.ProjectID
 		state.FunctionName = plan.FunctionName
 		state.FunctionCode = plan.FunctionCode
+		state.Entrypoint = plan.Entrypoint
+		state.Language = plan.Language
+		state.FunctionCodeHash = types.StringValue(hashAppFunctionCode(functionCode))
 	}
 
 	diags = resp.State.Set(ctx, &state)
@@ -274,4 +416,6 @@ func (r *appFunctionResource) ImportState(ctx context.Context, req resource.Impo
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("appservices_app_id"), idParts[1])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("function_name"), found_function_name)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("function_code"), found_function_code)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("language"), "javascript")...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("function_code_hash"), hashAppFunctionCode(found_function_code))...)
 }
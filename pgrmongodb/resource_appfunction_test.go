@@ -2,6 +2,9 @@ package pgrmongodb
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -49,6 +52,92 @@ func TestAccPGRMongoDBAppFunction(t *testing.T) {
 	})
 }
 
+func TestAccPGRMongoDBAppFunctionTypeScript(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+		resource "pgrmongodb_appfunction" "test" {
+			project_id = "000000000000000000000000"
+			appservices_app_id = "000000000000000000000000"
+			function_name = "my_tf_ts_function"
+			language = "typescript"
+			function_code = <<EOT
+exports = async (changeEvent: any): Promise<void> => {
+	const message: string = 'Function Code TS';
+	console.log(message);
+}
+EOT
+		}
+		`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_appfunction.test", "language", "typescript"),
+					resource.TestCheckResourceAttrSet("pgrmongodb_appfunction.test", "function_code_hash"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPGRMongoDBAppFunctionEntrypoint(t *testing.T) {
+	sourceDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(sourceDir, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sourceDir, "lib", "greeting.js"), []byte("module.exports = (name) => 'Hello ' + name;\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sourceDir, "main.js"), []byte("const greeting = require('./lib/greeting');\nexports = async (name) => greeting(name);\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+		resource "pgrmongodb_appfunction" "test" {
+			project_id = "000000000000000000000000"
+			appservices_app_id = "000000000000000000000000"
+			function_name = "my_tf_bundled_function"
+			entrypoint = "%s"
+		}
+		`, filepath.ToSlash(filepath.Join(sourceDir, "main.js"))),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("pgrmongodb_appfunction.test", "entrypoint"),
+					resource.TestCheckNoResourceAttr("pgrmongodb_appfunction.test", "function_code"),
+					resource.TestCheckResourceAttrSet("pgrmongodb_appfunction.test", "function_code_hash"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPGRMongoDBAppFunctionInvalidCode(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+		resource "pgrmongodb_appfunction" "test" {
+			project_id = "000000000000000000000000"
+			appservices_app_id = "000000000000000000000000"
+			function_name = "my_tf_function"
+			function_code = <<EOT
+exports = async (changeEvent) => {
+	console.log('Function Code 1';
+}
+EOT
+		}
+		`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`syntax error on line 2, column`),
+			},
+		},
+	})
+}
+
 func testAccCheckPGRMongoDBAppFunctionConfig(project_id string, appservices_app_id string, function_name string, function_id int) string {
 	if function_id == 1 {
 		return fmt.Sprintf(`
@@ -1,45 +1,76 @@
 package pgrmongodb
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"fmt"
-	"reflect"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
-	_ resource.Resource                = &appFunctionDependenciesResource{}
-	_ resource.ResourceWithConfigure   = &appFunctionDependenciesResource{}
-	_ resource.ResourceWithImportState = &appFunctionDependenciesResource{}
+	_ resource.Resource                   = &appFunctionDependenciesResource{}
+	_ resource.ResourceWithConfigure      = &appFunctionDependenciesResource{}
+	_ resource.ResourceWithImportState    = &appFunctionDependenciesResource{}
+	_ resource.ResourceWithValidateConfig = &appFunctionDependenciesResource{}
+	_ resource.ResourceWithUpgradeState   = &appFunctionDependenciesResource{}
+	_ resource.ResourceWithModifyPlan     = &appFunctionDependenciesResource{}
 )
 
+// dependency builds for a handful of packages usually finish within a few minutes
+const appFunctionDependenciesDefaultTimeout = 20 * time.Minute
+
 func NewAppFunctionDependencies() resource.Resource {
 	return &appFunctionDependenciesResource{}
 }
 
 type appFunctionDependenciesResource struct {
-	bearer_token string
+	bearer_token     string
+	npm_registry_url string
 }
 
 type appFunctionDependenciesResourceModel struct {
-	ID               types.String `tfsdk:"id"`
-	ProjectID        types.String `tfsdk:"project_id"`
-	AppServicesAppID types.String `tfsdk:"appservices_app_id"`
-	Dependencies     types.Set    `tfsdk:"dependencies"`
+	ID                types.String   `tfsdk:"id"`
+	ProjectID         types.String   `tfsdk:"project_id"`
+	AppServicesAppID  types.String   `tfsdk:"appservices_app_id"`
+	Dependencies      types.Set      `tfsdk:"dependencies"`
+	PackageJSON       types.String   `tfsdk:"package_json"`
+	DependencyArchive types.String   `tfsdk:"dependency_archive"`
+	SourceHash        types.String   `tfsdk:"source_hash"`
+	Mode              types.String   `tfsdk:"mode"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+type appFunctionDependencyModel struct {
+	Name            types.String `tfsdk:"name"`
+	Version         types.String `tfsdk:"version"`
+	ResolvedVersion types.String `tfsdk:"resolved_version"`
+}
+
+var appFunctionDependencyAttrTypes = map[string]attr.Type{
+	"name":             types.StringType,
+	"version":          types.StringType,
+	"resolved_version": types.StringType,
 }
 
 func (r *appFunctionDependenciesResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -49,6 +80,7 @@ func (r *appFunctionDependenciesResource) Metadata(_ context.Context, req resour
 func (r *appFunctionDependenciesResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "Manages a MongoDB Atlas App Services Function Dependencies",
+		Version:     1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Description: "identifier for resource.",
@@ -87,14 +119,83 @@ func (r *appFunctionDependenciesResource) Schema(ctx context.Context, _ resource
 					),
 				},
 			},
-			"dependencies": schema.SetAttribute{
-				Description: "List of function dependencies.",
-				Required:    true,
-				ElementType: types.StringType,
+			"dependencies": schema.SetNestedAttribute{
+				Description: "Set of npm packages to install for functions. When package_json or dependency_archive is set this lists the packages installed from it.",
+				Optional:    true,
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "npm package name, for example lodash or @scope/package.",
+							Required:    true,
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(
+									regexp.MustCompile(`^(@[a-z0-9-~][a-z0-9-._~]*/)?[a-z0-9-~][a-z0-9-._~]*$`),
+									"must be a valid npm package name",
+								),
+							},
+						},
+						"version": schema.StringAttribute{
+							Description: "npm package version, semver range or dist-tag, for example 4.17.21, ^4.17.0 or latest. Ranges and dist-tags are resolved against the provider npm_registry_url during plan.",
+							Required:    true,
+							Validators: []validator.String{
+								npmVersionValidator{},
+							},
+						},
+						"resolved_version": schema.StringAttribute{
+							Description: "Concrete version installed for version.",
+							Computed:    true,
+						},
+					},
+				},
 				PlanModifiers: []planmodifier.Set{
 					setplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"package_json": schema.StringAttribute{
+				Description: "Content of a package.json whose dependencies are installed in a single build, for example file(\"functions/package.json\").",
+				Optional:    true,
+				Validators: []validator.String{
+					jsonStringValidator{},
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("dependencies"),
+						path.MatchRoot("dependency_archive"),
+					),
+				},
+			},
+			"dependency_archive": schema.StringAttribute{
+				Description: "Path to a .zip, .tar, .tgz or .tar.gz archive with a node_modules directory that is uploaded and installed in a single build.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`\.(zip|tar|tgz|tar\.gz)$`),
+						"must be a path to a .zip, .tar, .tgz or .tar.gz archive",
+					),
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("dependencies"),
+					),
+				},
+			},
+			"mode": schema.StringAttribute{
+				Description: "authoritative (default) makes the app's packages match this resource and removes all packages on destroy. additive only installs, refreshes and removes the packages declared here and leaves other packages in the app alone.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("authoritative"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("authoritative", "additive"),
+				},
+			},
+			"source_hash": schema.StringAttribute{
+				Description: "SHA-256 of package_json or of the dependency_archive file. A change uploads the dependencies again. It is cleared on refresh when the installed packages no longer match the last upload, so packages changed outside of Terraform are corrected by uploading again.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
@@ -105,40 +206,244 @@ func (r *appFunctionDependenciesResource) Configure(_ context.Context, req resou
 	}
 
 	r.bearer_token = req.ProviderData.(providerData).bearer_token
+	r.npm_registry_url = req.ProviderData.(providerData).npm_registry_url
 }
 
-func (r *appFunctionDependenciesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan appFunctionDependenciesResourceModel
-	diags := req.Plan.Get(ctx, &plan)
+func (r *appFunctionDependenciesResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config appFunctionDependenciesResourceModel
+	diags := req.Config.Get(ctx, &config)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	projectID := plan.ProjectID.ValueString()
-	appServicesAppID := plan.AppServicesAppID.ValueString()
+	if config.Dependencies.IsNull() && config.PackageJSON.IsNull() && config.DependencyArchive.IsNull() {
+		resp.Diagnostics.AddError(
+			"Missing App Services Function Dependencies",
+			"One of dependencies, package_json or dependency_archive must be set.",
+		)
+		return
+	}
+	if config.Mode.ValueString() == "additive" && (!config.PackageJSON.IsNull() || !config.DependencyArchive.IsNull()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("mode"),
+			"Invalid App Services Function Dependencies Mode",
+			"mode additive requires dependencies. An uploaded package_json or dependency_archive replaces every package in the app.",
+		)
+		return
+	}
+	if config.Dependencies.IsUnknown() {
+		return
+	}
 
-	dependencies := make([]types.String, 0, len(plan.Dependencies.Elements()))
-	diags = plan.Dependencies.ElementsAs(ctx, &dependencies, false)
-	tflog.Info(ctx, strconv.Itoa(len(dependencies)))
+	dependencies := make([]appFunctionDependencyModel, 0, len(config.Dependencies.Elements()))
+	diags = config.Dependencies.ElementsAs(ctx, &dependencies, false)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	tflog.Info(ctx, "creating mongodb atlas app services function dependencies")
-	tflog.Debug(ctx, fmt.Sprintf("number of dependencies: %s", strconv.Itoa(len(dependencies))))
-	tflog.Debug(ctx, fmt.Sprintf("dependencies: %v", dependencies))
-	err := createAppFunctionDependencies(r.bearer_token, projectID, appServicesAppID, dependencies)
+	// App Services installs one version per package, so a name may only be listed once
+	seen := map[string]bool{}
+	for _, dependency := range dependencies {
+		if dependency.Name.IsUnknown() || dependency.Name.IsNull() {
+			continue
+		}
+		name := dependency.Name.ValueString()
+		if seen[name] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("dependencies"),
+				"Duplicate App Services Function Dependency",
+				fmt.Sprintf("dependency %s is listed more than once. Only one version of a package can be installed.", name),
+			)
+		}
+		seen[name] = true
+	}
+}
+
+func (r *appFunctionDependenciesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan appFunctionDependenciesResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.PackageJSON.IsUnknown() || plan.DependencyArchive.IsUnknown() {
+		plan.SourceHash = types.StringUnknown()
+		plan.Dependencies = types.SetUnknown(types.ObjectType{AttrTypes: appFunctionDependencyAttrTypes})
+		diags = resp.Plan.Set(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	_, archive, err := plan.dependencyArchive()
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error Creating App Services Function Dependencies",
-			"Could not create MongoDB Atlas App Services Function Dependencies. Received error: "+err.Error(),
+		resp.Diagnostics.AddAttributeError(
+			path.Root("dependency_archive"),
+			"Error Reading App Services Function Dependency Archive",
+			"Could not read dependency_archive. Received error: "+err.Error(),
 		)
 		return
 	}
+	if archive == nil {
+		plan.SourceHash = types.StringNull()
+		if !plan.Dependencies.IsUnknown() && !plan.Dependencies.IsNull() {
+			plan.Dependencies, diags = r.resolveDependencyVersions(ctx, plan.Dependencies)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	} else {
+		plan.SourceHash = types.StringValue(hashAppFunctionCode(string(archive)))
+	}
+
+	// packages installed from a package.json or archive are only known after the build
+	if !plan.SourceHash.IsNull() && !req.State.Raw.IsNull() {
+		var state appFunctionDependenciesResourceModel
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !state.SourceHash.Equal(plan.SourceHash) || !state.ProjectID.Equal(plan.ProjectID) || !state.AppServicesAppID.Equal(plan.AppServicesAppID) {
+			plan.Dependencies = types.SetUnknown(types.ObjectType{AttrTypes: appFunctionDependencyAttrTypes})
+		}
+	}
+
+	diags = resp.Plan.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *appFunctionDependenciesResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// version 0 stored dependencies as "name version" strings and used project_id as id
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed: true,
+					},
+					"project_id": schema.StringAttribute{
+						Required: true,
+					},
+					"appservices_app_id": schema.StringAttribute{
+						Required: true,
+					},
+					"dependencies": schema.SetAttribute{
+						Required:    true,
+						ElementType: types.StringType,
+					},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var priorState struct {
+					ID               types.String `tfsdk:"id"`
+					ProjectID        types.String `tfsdk:"project_id"`
+					AppServicesAppID types.String `tfsdk:"appservices_app_id"`
+					Dependencies     types.Set    `tfsdk:"dependencies"`
+				}
+				diags := req.State.Get(ctx, &priorState)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				priorDependencies := make([]string, 0, len(priorState.Dependencies.Elements()))
+				diags = priorState.Dependencies.ElementsAs(ctx, &priorDependencies, false)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				dependencies := make([]appFunctionDependency, 0, len(priorDependencies))
+				for _, v := range priorDependencies {
+					depTokens := strings.Fields(v)
+					if len(depTokens) != 2 {
+						resp.Diagnostics.AddError(
+							"Error Upgrading App Services Function Dependencies State",
+							fmt.Sprintf("Could not convert dependency %q, expected \"name version\".", v),
+						)
+						return
+					}
+					dependencies = append(dependencies, appFunctionDependency{Name: depTokens[0], Version: depTokens[1]})
+				}
+
+				upgradedState := appFunctionDependenciesResourceModel{
+					ID:               types.StringValue(appFunctionDependenciesID(priorState.ProjectID.ValueString(), priorState.AppServicesAppID.ValueString())),
+					ProjectID:        priorState.ProjectID,
+					AppServicesAppID: priorState.AppServicesAppID,
+					Mode:             types.StringValue("authoritative"),
+					Timeouts: timeouts.Value{
+						Object: types.ObjectNull(map[string]attr.Type{
+							"create": types.StringType,
+							"update": types.StringType,
+							"delete": types.StringType,
+						}),
+					},
+				}
+				upgradedState.Dependencies, diags = appFunctionDependenciesToSet(dependencies, nil)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				diags = resp.State.Set(ctx, upgradedState)
+				resp.Diagnostics.Append(diags...)
+			},
+		},
+	}
+}
+
+func (r *appFunctionDependenciesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan appFunctionDependenciesResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	plan.ID = types.StringValue(projectID)
+	createTimeout, diags := plan.Timeouts.Create(ctx, appFunctionDependenciesDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	projectID := plan.ProjectID.ValueString()
+	appServicesAppID := plan.AppServicesAppID.ValueString()
+
+	if !plan.SourceHash.IsNull() {
+		tflog.Info(ctx, "uploading mongodb atlas app services function dependency archive")
+		resp.Diagnostics.Append(r.uploadDependencyArchive(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		dependencies, diags := appFunctionDependenciesFromSet(ctx, plan.Dependencies)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		tflog.Info(ctx, "creating mongodb atlas app services function dependencies")
+		tflog.Debug(ctx, fmt.Sprintf("number of dependencies: %s", strconv.Itoa(len(dependencies))))
+		tflog.Debug(ctx, fmt.Sprintf("dependencies: %v", dependencies))
+		err := createAppFunctionDependencies(ctx, r.bearer_token, projectID, appServicesAppID, dependencies)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Creating App Services Function Dependencies",
+				"Could not create MongoDB Atlas App Services Function Dependencies. Received error: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	plan.ID = types.StringValue(appFunctionDependenciesID(projectID, appServicesAppID))
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -168,8 +473,40 @@ func (r *appFunctionDependenciesResource) Read(ctx context.Context, req resource
 		return
 	}
 
-	state.ID = types.StringValue(projectID)
-	state.Dependencies, diags = types.SetValueFrom(ctx, types.StringType, dependencies)
+	owned := make([]appFunctionDependencyModel, 0, len(state.Dependencies.Elements()))
+	diags = state.Dependencies.ElementsAs(ctx, &owned, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// the declared version or range is kept, the installed version is reported as resolved_version
+	declaredVersions := make(map[string]string, len(owned))
+	for _, v := range owned {
+		declaredVersions[v.Name.ValueString()] = v.Version.ValueString()
+	}
+	if state.Mode.ValueString() == "additive" {
+		// packages installed by other teams or the UI are not owned by this resource
+		dependencies = filterAppFunctionDependencies(dependencies, declaredVersions)
+	}
+	if !state.SourceHash.IsNull() {
+		// state holds the packages installed by the last upload. When the app no longer matches them the
+		// source hash is cleared, so the next plan uploads package_json or dependency_archive again
+		uploaded, uploadedDiags := appFunctionDependenciesFromSet(ctx, state.Dependencies)
+		resp.Diagnostics.Append(uploadedDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		added, changed, removed := diffAppFunctionDependencies(uploaded, dependencies)
+		if len(added) > 0 || len(changed) > 0 || len(removed) > 0 {
+			tflog.Warn(ctx, fmt.Sprintf("app services function dependencies changed outside of terraform. added: %v, changed: %v, removed: %v", added, changed, removed))
+			state.SourceHash = types.StringNull()
+		}
+		// the manifest declares the versions, so state reports what is installed
+		declaredVersions = nil
+	}
+
+	state.ID = types.StringValue(appFunctionDependenciesID(projectID, appServicesAppID))
+	state.Dependencies, diags = appFunctionDependenciesToSet(dependencies, declaredVersions)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -197,86 +534,111 @@ func (r *appFunctionDependenciesResource) Update(ctx context.Context, req resour
 		return
 	}
 
-	sameDependencies := reflect.DeepEqual(state.Dependencies, plan.Dependencies)
+	updateTimeout, diags := plan.Timeouts.Update(ctx, appFunctionDependenciesDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
 	hasChange := false
-	if state.ProjectID != plan.ProjectID || state.AppServicesAppID != plan.AppServicesAppID || !sameDependencies {
+	if !state.ProjectID.Equal(plan.ProjectID) || !state.AppServicesAppID.Equal(plan.AppServicesAppID) || !state.Dependencies.Equal(plan.Dependencies) {
 		hasChange = true
 	}
 
 	tflog.Info(ctx, "checking mongodb atlas app services function dependencies for deltas")
-	if hasChange {
+	if !plan.SourceHash.IsNull() {
+		if !state.SourceHash.Equal(plan.SourceHash) || !state.ProjectID.Equal(plan.ProjectID) || !state.AppServicesAppID.Equal(plan.AppServicesAppID) {
+			tflog.Info(ctx, "uploading mongodb atlas app services function dependency archive")
+			resp.Diagnostics.Append(r.uploadDependencyArchive(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			if !state.ProjectID.Equal(plan.ProjectID) || !state.AppServicesAppID.Equal(plan.AppServicesAppID) {
+				tflog.Info(ctx, "deleting mongodb atlas app services function dependencies")
+				err := r.deleteDependencies(ctx, state)
+				if err != nil {
+					resp.Diagnostics.AddError(
+						"Error Deleting App Services Function Dependencies",
+						"Could not delete MongoDB Atlas App Services Function Dependencies. Received error: "+err.Error(),
+					)
+					return
+				}
+			}
+		}
+		state = plan
+	} else if hasChange {
 		tflog.Info(ctx, "updating mongodb atlas app services function dependencies")
-		planElements := make([]types.String, 0, len(plan.Dependencies.Elements()))
-		diags = plan.Dependencies.ElementsAs(ctx, &planElements, false)
+		planElements, diags := appFunctionDependenciesFromSet(ctx, plan.Dependencies)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
-		if !sameDependencies {
+		if state.ProjectID.Equal(plan.ProjectID) && state.AppServicesAppID.Equal(plan.AppServicesAppID) {
 			tflog.Info(ctx, "detected delta in dependencies from state to current plan")
-			// different dependencies means we have to capture the delta and create/delete accordingly
-			stateElements := make([]types.String, 0, len(state.Dependencies.Elements()))
-			diags = state.Dependencies.ElementsAs(ctx, &stateElements, false)
+			stateElements, diags := appFunctionDependenciesFromSet(ctx, state.Dependencies)
 			resp.Diagnostics.Append(diags...)
 			if resp.Diagnostics.HasError() {
 				return
 			}
-			var toBeAdded []basetypes.StringValue
-			var toBeRemoved []basetypes.StringValue
-			// find elements in plan that aren't in the state (to be added)
-			for i := 0; i < len(planElements); i++ {
-				found := false
-				for j := 0; j < len(stateElements); j++ {
-					if planElements[i] == stateElements[j] {
-						found = true
-						break
-					}
-				}
-				if !found {
-					toBeAdded = append(toBeAdded, planElements[i])
+			if plan.Mode.ValueString() == "additive" && state.Mode.ValueString() != "additive" {
+				// after an import or a switch from authoritative, state also lists packages this resource does not own.
+				// Once state is additive it only holds owned packages, so packages removed from the plan are uninstalled
+				planVersions := make(map[string]string, len(planElements))
+				for _, v := range planElements {
+					planVersions[v.Name] = v.Version
 				}
+				stateElements = filterAppFunctionDependencies(stateElements, planVersions)
 			}
-			for i := 0; i < len(toBeAdded); i++ {
-				depTokens := strings.Split(toBeAdded[i].ValueString(), " ")
-				err := manageAppFunctionDependency(r.bearer_token, plan.ProjectID.ValueString(), plan.AppServicesAppID.ValueString(), depTokens[0], depTokens[1], "PUT")
+			toBeAdded, toBeUpgraded, toBeRemoved := diffAppFunctionDependencies(stateElements, planElements)
+			tflog.Debug(ctx, fmt.Sprintf("dependencies to add: %v, to upgrade: %v, to remove: %v", toBeAdded, toBeUpgraded, toBeRemoved))
+
+			// a PUT with a new version replaces the installed version of a package, so upgrades and additions are the same call
+			for _, dependency := range append(toBeAdded, toBeUpgraded...) {
+				err := manageAppFunctionDependency(ctx, r.bearer_token, plan.ProjectID.ValueString(), plan.AppServicesAppID.ValueString(), dependency.Name, dependency.Version, "PUT")
 				if err != nil {
 					resp.Diagnostics.AddError(
-						"Error Creating App Services Function Depenendencies",
-						"Could not create MongoDB Atlas App Services Function Depenendencies. Received error: "+err.Error()+","+req.State.Raw.String(),
+						"Error Updating App Services Function Dependencies",
+						"Could not install MongoDB Atlas App Services Function Dependency "+dependency.Name+" "+dependency.Version+". Received error: "+err.Error(),
 					)
 					return
 				}
 			}
-			// find elements in state that aren't in the plan (to be removed)
-			for i := 0; i < len(stateElements); i++ {
-				found := false
-				for j := 0; j < len(planElements); j++ {
-					if stateElements[i] == planElements[j] {
-						found = true
-						break
-					}
-				}
-				if !found {
-					toBeRemoved = append(toBeRemoved, stateElements[i])
-				}
-			}
-			for i := 0; i < len(toBeRemoved); i++ {
-				depTokens := strings.Split(toBeRemoved[i].ValueString(), " ")
-				err := manageAppFunctionDependency(r.bearer_token, state.ProjectID.ValueString(), state.AppServicesAppID.ValueString(), depTokens[0], depTokens[1], "DELETE")
+			for _, dependency := range toBeRemoved {
+				err := manageAppFunctionDependency(ctx, r.bearer_token, state.ProjectID.ValueString(), state.AppServicesAppID.ValueString(), dependency.Name, dependency.Version, "DELETE")
 				if err != nil {
 					resp.Diagnostics.AddError(
-						"Error Deleting App Services Function Depenendencies",
-						"Could not delete MongoDB Atlas App Services Function Depenendencies. Received error: "+err.Error()+","+req.State.Raw.String(),
+						"Error Deleting App Services Function Dependencies",
+						"Could not delete MongoDB Atlas App Services Function Dependency "+dependency.Name+". Received error: "+err.Error(),
 					)
 					return
 				}
 			}
+
+			tflog.Info(ctx, "verifying mongodb atlas app services function dependencies")
+			installed, err := getAppFunctionDependencies(r.bearer_token, plan.ProjectID.ValueString(), plan.AppServicesAppID.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error Reading App Services Function Dependencies",
+					"Could not read MongoDB Atlas App Services Function Dependencies after update. Received error: "+err.Error(),
+				)
+				return
+			}
+			missing, mismatched, _ := diffAppFunctionDependencies(installed, planElements)
+			if len(missing) > 0 || len(mismatched) > 0 {
+				resp.Diagnostics.AddError(
+					"Error Updating App Services Function Dependencies",
+					fmt.Sprintf("MongoDB Atlas App Services Function Dependencies do not match the plan after update. Not installed: %v, installed with a different version: %v. Installed: %v", missing, mismatched, installed),
+				)
+				return
+			}
 		} else {
 			// different project or app services apps do a full create on the new project/app and full delete from the old project/app
 			tflog.Info(ctx, "creating mongodb atlas app services function dependencies")
 			tflog.Debug(ctx, fmt.Sprintf("number of plan dependencies to create: %s", strconv.Itoa(len(planElements))))
 			tflog.Debug(ctx, fmt.Sprintf("dependencies: %v", planElements))
-			err := createAppFunctionDependencies(r.bearer_token, plan.ProjectID.ValueString(), plan.AppServicesAppID.ValueString(), planElements)
+			err := createAppFunctionDependencies(ctx, r.bearer_token, plan.ProjectID.ValueString(), plan.AppServicesAppID.ValueString(), planElements)
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error Creating App Services Function Depenendencies",
@@ -285,7 +647,7 @@ func (r *appFunctionDependenciesResource) Update(ctx context.Context, req resour
 				return
 			}
 			tflog.Info(ctx, "deleting mongodb atlas app services function dependencies")
-			err = deleteAllAppFunctionDependencies(r.bearer_token, state.ProjectID.ValueString(), state.AppServicesAppID.ValueString())
+			err = r.deleteDependencies(ctx, state)
 			if err != nil {
 				resp.Diagnostics.AddError(
 					"Error Deleting App Services Function",
@@ -295,11 +657,16 @@ func (r *appFunctionDependenciesResource) Update(ctx context.Context, req resour
 			}
 		}
 
-		state.ID = plan.ProjectID
+		state.ID = types.StringValue(appFunctionDependenciesID(plan.ProjectID.ValueString(), plan.AppServicesAppID.ValueString()))
 		state.ProjectID = plan.ProjectID
 		state.AppServicesAppID = plan.AppServicesAppID
 		state.Dependencies = plan.Dependencies
 	}
+	state.PackageJSON = plan.PackageJSON
+	state.DependencyArchive = plan.DependencyArchive
+	state.SourceHash = plan.SourceHash
+	state.Timeouts = plan.Timeouts
+	state.Mode = plan.Mode
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -316,11 +683,16 @@ func (r *appFunctionDependenciesResource) Delete(ctx context.Context, req resour
 		return
 	}
 
-	projectID := state.ProjectID.ValueString()
-	appServicesAppID := state.AppServicesAppID.ValueString()
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, appFunctionDependenciesDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
 
 	tflog.Info(ctx, "deleting mongodb atlas app services function dependencies")
-	err := deleteAllAppFunctionDependencies(r.bearer_token, projectID, appServicesAppID)
+	err := r.deleteDependencies(ctx, state)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error Deleting App Services Function Dependencies",
@@ -332,11 +704,14 @@ func (r *appFunctionDependenciesResource) Delete(ctx context.Context, req resour
 
 func (r *appFunctionDependenciesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	idParts := strings.Split(req.ID, ",")
+	if len(idParts) != 2 {
+		idParts = strings.Split(req.ID, "/")
+	}
 
 	if len(idParts) != 2 {
 		resp.Diagnostics.AddError(
 			"Error Importing App Services Function Dependencies",
-			"Could not import MongoDB Atlas App Services Function Dependencies.\nPlease ensure you run terraform import with project_id,appservices_app_id",
+			"Could not import MongoDB Atlas App Services Function Dependencies.\nPlease ensure you run terraform import with project_id,appservices_app_id or project_id/appservices_app_id",
 		)
 		return
 	}
@@ -350,8 +725,206 @@ func (r *appFunctionDependenciesResource) ImportState(ctx context.Context, req r
 		return
 	}
 
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[0])...)
+	dependenciesSet, diags := appFunctionDependenciesToSet(dependencies, nil)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), appFunctionDependenciesID(idParts[0], idParts[1]))...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), idParts[0])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("appservices_app_id"), idParts[1])...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("dependencies"), dependencies)...)
+	// mode is left unset so the next plan takes it from the configuration
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("dependencies"), dependenciesSet)...)
+}
+
+// removes the packages this resource manages from the app
+func (r *appFunctionDependenciesResource) deleteDependencies(ctx context.Context, model appFunctionDependenciesResourceModel) error {
+	if model.Mode.ValueString() != "additive" {
+		return deleteAllAppFunctionDependencies(ctx, r.bearer_token, model.ProjectID.ValueString(), model.AppServicesAppID.ValueString())
+	}
+	owned, diags := appFunctionDependenciesFromSet(ctx, model.Dependencies)
+	if diags.HasError() {
+		return fmt.Errorf("unable to read dependencies from state")
+	}
+	return deleteAppFunctionDependencies(ctx, r.bearer_token, model.ProjectID.ValueString(), model.AppServicesAppID.ValueString(), owned)
+}
+
+// keeps the installed packages whose name is a key of owned
+func filterAppFunctionDependencies(installed []appFunctionDependency, owned map[string]string) []appFunctionDependency {
+	filtered := make([]appFunctionDependency, 0, len(owned))
+	for _, v := range installed {
+		if _, ok := owned[v.Name]; ok {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
+// fills in resolved_version for every dependency, resolving ranges and dist-tags against the npm registry
+func (r *appFunctionDependenciesResource) resolveDependencyVersions(ctx context.Context, set types.Set) (types.Set, diag.Diagnostics) {
+	models := make([]appFunctionDependencyModel, 0, len(set.Elements()))
+	diags := set.ElementsAs(ctx, &models, false)
+	if diags.HasError() {
+		return set, diags
+	}
+
+	registryURL := r.npm_registry_url
+	if registryURL == "" {
+		registryURL = defaultNpmRegistryURL
+	}
+	elements := make([]attr.Value, 0, len(models))
+	for _, v := range models {
+		resolved := types.StringUnknown()
+		if !v.Name.IsUnknown() && !v.Version.IsUnknown() {
+			version, err := resolveNpmVersion(registryURL, v.Name.ValueString(), v.Version.ValueString())
+			if err != nil {
+				diags.AddAttributeError(
+					path.Root("dependencies"),
+					"Error Resolving App Services Function Dependency Version",
+					fmt.Sprintf("Could not resolve version %s of %s. Received error: %s", v.Version.ValueString(), v.Name.ValueString(), err),
+				)
+				continue
+			}
+			resolved = types.StringValue(version)
+		}
+		element, objectDiags := types.ObjectValue(appFunctionDependencyAttrTypes, map[string]attr.Value{
+			"name":             v.Name,
+			"version":          v.Version,
+			"resolved_version": resolved,
+		})
+		diags.Append(objectDiags...)
+		elements = append(elements, element)
+	}
+	if diags.HasError() {
+		return set, diags
+	}
+	resolvedSet, setDiags := types.SetValue(types.ObjectType{AttrTypes: appFunctionDependencyAttrTypes}, elements)
+	diags.Append(setDiags...)
+	return resolvedSet, diags
+}
+
+// uploads package_json or dependency_archive and records the installed packages in model
+func (r *appFunctionDependenciesResource) uploadDependencyArchive(ctx context.Context, model *appFunctionDependenciesResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	fileName, archive, err := model.dependencyArchive()
+	if err != nil {
+		diags.AddAttributeError(path.Root("dependency_archive"), "Error Reading App Services Function Dependency Archive", "Could not read dependency_archive. Received error: "+err.Error())
+		return diags
+	}
+	err = uploadAppFunctionDependencyArchive(ctx, r.bearer_token, model.ProjectID.ValueString(), model.AppServicesAppID.ValueString(), fileName, archive)
+	if err != nil {
+		diags.AddError("Error Uploading App Services Function Dependencies", "Could not upload MongoDB Atlas App Services Function Dependencies. Received error: "+err.Error())
+		return diags
+	}
+
+	dependencies, err := getAppFunctionDependencies(r.bearer_token, model.ProjectID.ValueString(), model.AppServicesAppID.ValueString())
+	if err != nil {
+		diags.AddError("Error Reading App Services Function Dependencies", "Could not read MongoDB Atlas App Services Function Dependencies after upload. Received error: "+err.Error())
+		return diags
+	}
+	var setDiags diag.Diagnostics
+	model.Dependencies, setDiags = appFunctionDependenciesToSet(dependencies, nil)
+	diags.Append(setDiags...)
+	model.ID = types.StringValue(appFunctionDependenciesID(model.ProjectID.ValueString(), model.AppServicesAppID.ValueString()))
+	return diags
+}
+
+// returns the archive to upload for package_json or dependency_archive, or nil when dependencies are listed individually.
+// A package_json is wrapped in a zip archive, App Services builds node_modules from it
+func (m appFunctionDependenciesResourceModel) dependencyArchive() (string, []byte, error) {
+	if !m.DependencyArchive.IsNull() {
+		archive, err := os.ReadFile(m.DependencyArchive.ValueString())
+		if err != nil {
+			return "", nil, err
+		}
+		return filepath.Base(m.DependencyArchive.ValueString()), archive, nil
+	}
+	if m.PackageJSON.IsNull() {
+		return "", nil, nil
+	}
+
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	file, err := writer.Create("package.json")
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := file.Write([]byte(m.PackageJSON.ValueString())); err != nil {
+		return "", nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return "", nil, err
+	}
+	return "package.zip", archive.Bytes(), nil
+}
+
+// compares dependencies by package name. A name in both with a different version is an upgrade, not an add and a remove
+func diffAppFunctionDependencies(current []appFunctionDependency, desired []appFunctionDependency) ([]appFunctionDependency, []appFunctionDependency, []appFunctionDependency) {
+	currentVersions := make(map[string]string, len(current))
+	for _, v := range current {
+		currentVersions[v.Name] = v.Version
+	}
+	desiredNames := make(map[string]bool, len(desired))
+
+	var added, upgraded, removed []appFunctionDependency
+	for _, v := range desired {
+		desiredNames[v.Name] = true
+		version, ok := currentVersions[v.Name]
+		if !ok {
+			added = append(added, v)
+		} else if version != v.Version {
+			upgraded = append(upgraded, v)
+		}
+	}
+	for _, v := range current {
+		if !desiredNames[v.Name] {
+			removed = append(removed, v)
+		}
+	}
+	return added, upgraded, removed
+}
+
+// several apps in one project each have their own dependencies, so the id includes the app
+func appFunctionDependenciesID(projectID string, appServicesAppID string) string {
+	return projectID + "/" + appServicesAppID
+}
+
+// returns the concrete versions to install, the resolved_version where known and otherwise version
+func appFunctionDependenciesFromSet(ctx context.Context, set types.Set) ([]appFunctionDependency, diag.Diagnostics) {
+	models := make([]appFunctionDependencyModel, 0, len(set.Elements()))
+	diags := set.ElementsAs(ctx, &models, false)
+	dependencies := make([]appFunctionDependency, 0, len(models))
+	for _, v := range models {
+		version := v.Version.ValueString()
+		if !v.ResolvedVersion.IsNull() && !v.ResolvedVersion.IsUnknown() {
+			version = v.ResolvedVersion.ValueString()
+		}
+		dependencies = append(dependencies, appFunctionDependency{Name: v.Name.ValueString(), Version: version})
+	}
+	return dependencies, diags
+}
+
+// builds the dependencies attribute from installed packages. declaredVersions maps package names to the configured
+// version or range, packages without an entry report the installed version for both
+func appFunctionDependenciesToSet(dependencies []appFunctionDependency, declaredVersions map[string]string) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	elements := make([]attr.Value, 0, len(dependencies))
+	for _, v := range dependencies {
+		version, ok := declaredVersions[v.Name]
+		if !ok {
+			version = v.Version
+		}
+		element, objectDiags := types.ObjectValue(appFunctionDependencyAttrTypes, map[string]attr.Value{
+			"name":             types.StringValue(v.Name),
+			"version":          types.StringValue(version),
+			"resolved_version": types.StringValue(v.Version),
+		})
+		diags.Append(objectDiags...)
+		elements = append(elements, element)
+	}
+	set, setDiags := types.SetValue(types.ObjectType{AttrTypes: appFunctionDependencyAttrTypes}, elements)
+	diags.Append(setDiags...)
+	return set, diags
 }
@@ -2,9 +2,11 @@ package pgrmongodb
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
 
 func TestAccPGRMongoDBAppFunctionDependencies(t *testing.T) {
@@ -19,8 +21,9 @@ func TestAccPGRMongoDBAppFunctionDependencies(t *testing.T) {
 				Config: providerConfig + testAccCheckPGRMongoDBAppFunctionDependenciesConfig(project_id, appservices_app_id, 2),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("pgrmongodb_appfunctiondependencies.test", "project_id"),
-					resource.TestCheckTypeSetElemAttr("pgrmongodb_appfunctiondependencies.test", "dependencies.*", "uuidv1 1.6.14"),
-					resource.TestCheckTypeSetElemAttr("pgrmongodb_appfunctiondependencies.test", "dependencies.*", "simple-test-package 0.2.2"),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.test", "id", project_id+"/"+appservices_app_id),
+					resource.TestCheckTypeSetElemNestedAttrs("pgrmongodb_appfunctiondependencies.test", "dependencies.*", map[string]string{"name": "uuidv1", "version": "1.6.14"}),
+					resource.TestCheckTypeSetElemNestedAttrs("pgrmongodb_appfunctiondependencies.test", "dependencies.*", map[string]string{"name": "simple-test-package", "version": "0.2.2"}),
 					resource.TestCheckResourceAttrSet("pgrmongodb_appfunctiondependencies.test", "appservices_app_id"),
 				),
 			},
@@ -29,22 +32,304 @@ func TestAccPGRMongoDBAppFunctionDependencies(t *testing.T) {
 				Config: providerConfig + testAccCheckPGRMongoDBAppFunctionDependenciesConfig(project_id, appservices_app_id, 1),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttrSet("pgrmongodb_appfunctiondependencies.test", "project_id"),
-					resource.TestCheckTypeSetElemAttr("pgrmongodb_appfunctiondependencies.test", "dependencies.*", "uuidv1 1.6.14"),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.test", "dependencies.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("pgrmongodb_appfunctiondependencies.test", "dependencies.*", map[string]string{"name": "uuidv1", "version": "1.6.14"}),
 					resource.TestCheckResourceAttrSet("pgrmongodb_appfunctiondependencies.test", "appservices_app_id"),
 				),
 			},
+			// Upgrade in place and Read testing
+			{
+				Config: providerConfig + testAccCheckPGRMongoDBAppFunctionDependenciesConfig(project_id, appservices_app_id, 3),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.test", "dependencies.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("pgrmongodb_appfunctiondependencies.test", "dependencies.*", map[string]string{"name": "uuidv1", "version": "1.6.13"}),
+				),
+			},
 			// Delete testing automatically occurs in TestCase
 		},
 	})
 }
 
+func TestAccPGRMongoDBAppFunctionDependenciesPackageJSON(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	package_json = jsonencode({
+		dependencies = {
+			"uuidv1" = "1.6.14"
+			"simple-test-package" = "0.2.2"
+		}
+	})
+
+	timeouts {
+		create = "30m"
+	}
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("pgrmongodb_appfunctiondependencies.test", "source_hash"),
+					resource.TestCheckTypeSetElemNestedAttrs("pgrmongodb_appfunctiondependencies.test", "dependencies.*", map[string]string{"name": "uuidv1", "version": "1.6.14"}),
+					resource.TestCheckTypeSetElemNestedAttrs("pgrmongodb_appfunctiondependencies.test", "dependencies.*", map[string]string{"name": "simple-test-package", "version": "0.2.2"}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPGRMongoDBAppFunctionDependenciesVersionRange(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	dependencies = [
+		{ name = "uuidv1", version = "~1.6.13" },
+		{ name = "simple-test-package", version = "latest" },
+	]
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("pgrmongodb_appfunctiondependencies.test", "dependencies.*", map[string]string{"name": "uuidv1", "version": "~1.6.13", "resolved_version": "1.6.14"}),
+					resource.TestCheckTypeSetElemNestedAttrs("pgrmongodb_appfunctiondependencies.test", "dependencies.*", map[string]string{"name": "simple-test-package", "version": "latest"}),
+					resource.TestMatchTypeSetElemNestedAttrs("pgrmongodb_appfunctiondependencies.test", "dependencies.*", map[string]*regexp.Regexp{"name": regexp.MustCompile(`^simple-test-package$`), "resolved_version": regexp.MustCompile(`^\d+\.\d+\.\d+`)}),
+				),
+			},
+		},
+	})
+}
+
+// note this expects packages installed outside of Terraform in the app to be left in place
+func TestAccPGRMongoDBAppFunctionDependenciesAdditive(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	mode = "additive"
+	dependencies = [
+		{ name = "uuidv1", version = "1.6.14" },
+	]
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.test", "mode", "additive"),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.test", "dependencies.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("pgrmongodb_appfunctiondependencies.test", "dependencies.*", map[string]string{"name": "uuidv1", "version": "1.6.14"}),
+				),
+			},
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	mode = "additive"
+	dependencies = [
+		{ name = "uuidv1", version = "1.6.14" },
+		{ name = "simple-test-package", version = "0.2.2" },
+	]
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.test", "dependencies.#", "2"),
+				),
+			},
+			// a package removed from the configuration is uninstalled from the app
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	mode = "additive"
+	dependencies = [
+		{ name = "uuidv1", version = "1.6.14" },
+	]
+}
+
+data "pgrmongodb_appfunctiondependencies" "installed" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+
+	depends_on = [pgrmongodb_appfunctiondependencies.test]
+}
+
+output "simple_test_package_installed" {
+	value = contains([for d in data.pgrmongodb_appfunctiondependencies.installed.dependencies : d.name], "simple-test-package")
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.test", "dependencies.#", "1"),
+					resource.TestCheckOutput("simple_test_package_installed", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPGRMongoDBAppFunctionDependenciesAdditiveImport(t *testing.T) {
+	// simple-test-package is installed by a second resource, so it is not managed by pgrmongodb_appfunctiondependencies.test.
+	// The plan after each apply is empty only if it is still installed
+	config := func(mode string) string {
+		return providerConfig + fmt.Sprintf(`
+resource "pgrmongodb_appfunctiondependencies" "other" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	mode = "additive"
+	dependencies = [
+		{ name = "simple-test-package", version = "0.2.2" },
+	]
+}
+
+resource "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	mode = "%s"
+	dependencies = [
+		{ name = "uuidv1", version = "1.6.14" },
+	]
+
+	depends_on = [pgrmongodb_appfunctiondependencies.other]
+}`, mode)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// authoritative refreshes simple-test-package into state and plans to remove it
+			{
+				Config:             config("authoritative"),
+				ExpectNonEmptyPlan: true,
+			},
+			// switching to additive drops simple-test-package from state without removing it from the app
+			{
+				Config: config("additive"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.test", "mode", "additive"),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.test", "dependencies.#", "1"),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.other", "dependencies.#", "1"),
+				),
+			},
+			// import loads every installed package and leaves mode to the configuration
+			{
+				ResourceName:       "pgrmongodb_appfunctiondependencies.test",
+				ImportState:        true,
+				ImportStateId:      "000000000000000000000000,000000000000000000000000",
+				ImportStatePersist: true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 imported resource, got %d", len(states))
+					}
+					if count := states[0].Attributes["dependencies.#"]; count != "2" {
+						return fmt.Errorf("expected 2 imported dependencies, got %s", count)
+					}
+					if mode, ok := states[0].Attributes["mode"]; ok && mode != "" {
+						return fmt.Errorf("expected mode to be unset after import, got %s", mode)
+					}
+					return nil
+				},
+			},
+			{
+				Config: config("additive"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.test", "mode", "additive"),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.test", "dependencies.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("pgrmongodb_appfunctiondependencies.test", "dependencies.*", map[string]string{"name": "uuidv1", "version": "1.6.14"}),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctiondependencies.other", "dependencies.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPGRMongoDBAppFunctionDependenciesInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	dependencies = [
+		{ name = "uuidv1", version = "1.6.14" },
+		{ name = "uuidv1", version = "1.6.13" },
+	]
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Duplicate App Services Function Dependency`),
+			},
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	dependencies = [
+		{ name = "Not A Package", version = "1.0.0" },
+	]
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must be a valid npm package name`),
+			},
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	dependencies = [
+		{ name = "uuidv1", version = ">>1.6" },
+	]
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid npm Version`),
+			},
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Missing App Services Function Dependencies`),
+			},
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_appfunctiondependencies" "test" {
+	project_id = "000000000000000000000000"
+	appservices_app_id = "000000000000000000000000"
+	mode = "additive"
+	package_json = jsonencode({ dependencies = { "uuidv1" = "1.6.14" } })
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid App Services Function Dependencies Mode`),
+			},
+		},
+	})
+}
+
 func testAccCheckPGRMongoDBAppFunctionDependenciesConfig(project_id string, appservices_app_id string, id int) string {
-	if id == 1 {
+	if id == 3 {
+		return fmt.Sprintf(`
+		resource "pgrmongodb_appfunctiondependencies" "test" {
+			project_id = "%s"
+			appservices_app_id = "%s"
+			dependencies = [
+				{ name = "uuidv1", version = "1.6.13" },
+			]
+		}
+		`, project_id, appservices_app_id)
+	} else if id == 1 {
 		return fmt.Sprintf(`
 		resource "pgrmongodb_appfunctiondependencies" "test" {
 			project_id = "%s"
 			appservices_app_id = "%s"
-			dependencies = ["uuidv1 1.6.14"]
+			dependencies = [
+				{ name = "uuidv1", version = "1.6.14" },
+			]
 		}
 		`, project_id, appservices_app_id)
 	} else {
@@ -52,7 +337,10 @@ func testAccCheckPGRMongoDBAppFunctionDependenciesConfig(project_id string, apps
 		resource "pgrmongodb_appfunctiondependencies" "test" {
 			project_id = "%s"
 			appservices_app_id = "%s"
-			dependencies = ["uuidv1 1.6.14", "simple-test-package 0.2.2"]
+			dependencies = [
+				{ name = "uuidv1", version = "1.6.14" },
+				{ name = "simple-test-package", version = "0.2.2" },
+			]
 		}
 		`, project_id, appservices_app_id)
 	}
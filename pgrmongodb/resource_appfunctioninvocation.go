@@ -0,0 +1,399 @@
+package pgrmongodb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource               = &appFunctionInvocationResource{}
+	_ resource.ResourceWithConfigure  = &appFunctionInvocationResource{}
+	_ resource.ResourceWithModifyPlan = &appFunctionInvocationResource{}
+)
+
+func NewAppFunctionInvocationResource() resource.Resource {
+	return &appFunctionInvocationResource{}
+}
+
+type appFunctionInvocationResource struct {
+	bearer_token string
+}
+
+type appFunctionInvocationResourceModel struct {
+	ID                      types.String `tfsdk:"id"`
+	ProjectID               types.String `tfsdk:"project_id"`
+	AppServicesAppID        types.String `tfsdk:"appservices_app_id"`
+	FunctionName            types.String `tfsdk:"function_name"`
+	FunctionArgs            types.List   `tfsdk:"function_args"`
+	FunctionArgsJSON        types.List   `tfsdk:"function_args_json"`
+	ExecutionTimeout        types.Int64  `tfsdk:"execution_timeout"`
+	Triggers                types.Map    `tfsdk:"triggers"`
+	IgnoreErrors            types.Bool   `tfsdk:"ignore_function_errors"`
+	DestroyFunctionName     types.String `tfsdk:"destroy_function_name"`
+	DestroyFunctionArgsJSON types.List   `tfsdk:"destroy_function_args_json"`
+	MaxAttempts             types.Int64  `tfsdk:"max_attempts"`
+	RetryOn                 types.Set    `tfsdk:"retry_on"`
+	RetryDelay              types.String `tfsdk:"retry_delay"`
+	Expect                  types.List   `tfsdk:"expect"`
+	LastRun                 types.String `tfsdk:"last_run"`
+	ResultJSON              types.String `tfsdk:"result_json"`
+	Logs                    types.List   `tfsdk:"logs"`
+	Error                   types.String `tfsdk:"error"`
+}
+
+func (r *appFunctionInvocationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_appfunction_invocation"
+}
+
+func (r *appFunctionInvocationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Executes a MongoDB Atlas App Services Function on create and whenever triggers change",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "identifier for resource.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				Description: "MongoDB Atlas project identifier. Sometime referred to as group id.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal project_id",
+					),
+				},
+			},
+			"appservices_app_id": schema.StringAttribute{
+				Description: "MongoDB Atlas App Services app id to execute the function in.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal appservices_app_id",
+					),
+				},
+			},
+			"function_name": schema.StringAttribute{
+				Description: "Name of function to execute.",
+				Required:    true,
+			},
+			"function_args": schema.ListAttribute{
+				Description: "Ordered list of string arguments to pass to the function for execution.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.ConflictsWith(path.MatchRoot("function_args_json")),
+				},
+			},
+			"function_args_json": schema.ListAttribute{
+				Description: "Ordered list of JSON encoded arguments to pass to the function for execution, for example [jsonencode(42), jsonencode({ dryRun = true })]. Values are sent to the function unchanged.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(jsonStringValidator{}),
+				},
+			},
+			"execution_timeout": schema.Int64Attribute{
+				Description: "Sets the timeout value for function invocation (seconds).",
+				Optional:    true,
+			},
+			"triggers": schema.MapAttribute{
+				Description: "Arbitrary map of values that, when changed, will execute the function again. Changes to other arguments do not execute the function.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"ignore_function_errors": schema.BoolAttribute{
+				Description: "Do not fail when the function throws or returns an object with an error field. The error is still reported in the error attribute.",
+				Optional:    true,
+			},
+			"destroy_function_name": schema.StringAttribute{
+				Description: "Name of function to execute when this resource is destroyed.",
+				Optional:    true,
+			},
+			"destroy_function_args_json": schema.ListAttribute{
+				Description: "Ordered list of JSON encoded arguments to pass to destroy_function_name.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(jsonStringValidator{}),
+					listvalidator.AlsoRequires(path.MatchRoot("destroy_function_name")),
+				},
+			},
+			"last_run": schema.StringAttribute{
+				Description: "Timestamp for the last time this function executed.",
+				Computed:    true,
+			},
+			"result_json": schema.StringAttribute{
+				Description: "JSON encoded value returned by the function.",
+				Computed:    true,
+			},
+			"logs": schema.ListAttribute{
+				Description: "Log lines written by the function during execution.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"error": schema.StringAttribute{
+				Description: "Error reported by the function, empty when the function succeeded.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"expect": appFunctionExpectResourceBlock(),
+		},
+	}
+	for name, attribute := range appFunctionRetryResourceAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+func (r *appFunctionInvocationResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.bearer_token = req.ProviderData.(providerData).bearer_token
+}
+
+func (r *appFunctionInvocationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var state appFunctionInvocationResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	var plan appFunctionInvocationResourceModel
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the previous result is kept unless triggers change and the function runs again
+	if plan.Triggers.Equal(state.Triggers) {
+		plan.LastRun = state.LastRun
+		plan.ResultJSON = state.ResultJSON
+		plan.Logs = state.Logs
+		plan.Error = state.Error
+	} else {
+		plan.LastRun = types.StringUnknown()
+		plan.ResultJSON = types.StringUnknown()
+		plan.Logs = types.ListUnknown(types.StringType)
+		plan.Error = types.StringUnknown()
+	}
+
+	diags = resp.Plan.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *appFunctionInvocationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan appFunctionInvocationResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating App Services Function Invocation",
+			"Could not generate resource identifier. Received error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Info(ctx, "executing mongodb atlas app services function")
+	resp.Diagnostics.Append(r.invoke(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(id)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *appFunctionInvocationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// an invocation has nothing to refresh, the state holds the result of the last execution
+	var state appFunctionInvocationResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *appFunctionInvocationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state appFunctionInvocationResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan appFunctionInvocationResourceModel
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "checking mongodb atlas app services function invocation triggers for deltas")
+	if !plan.Triggers.Equal(state.Triggers) {
+		tflog.Info(ctx, "executing mongodb atlas app services function")
+		resp.Diagnostics.Append(r.invoke(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *appFunctionInvocationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state appFunctionInvocationResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.DestroyFunctionName.IsNull() {
+		return
+	}
+
+	functionArgsJSON := make([]string, 0, len(state.DestroyFunctionArgsJSON.Elements()))
+	diags = state.DestroyFunctionArgsJSON.ElementsAs(ctx, &functionArgsJSON, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	arguments, err := appFunctionArguments(nil, functionArgsJSON)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid MongoDB Atlas App Services Function Arguments",
+			err.Error(),
+		)
+		return
+	}
+
+	retryPolicy, diags := appFunctionRetryPolicyFromConfig(ctx, state.MaxAttempts, state.RetryOn, state.RetryDelay)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "executing mongodb atlas app services destroy function")
+	functionName := state.DestroyFunctionName.ValueString()
+	execution, err := executeAppServicesFunctionWithRetry(r.bearer_token, state.ProjectID.ValueString(), state.AppServicesAppID.ValueString(), functionName, arguments, state.ExecutionTimeout.ValueInt64(), "", retryPolicy)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Execute MongoDB Atlas App Services Function",
+			err.Error(),
+		)
+		return
+	}
+	if execution.Error != "" && !state.IgnoreErrors.ValueBool() {
+		resp.Diagnostics.AddError(
+			"MongoDB Atlas App Services Function Reported an Error",
+			fmt.Sprintf("app function %s reported an error%s: %s\n\nLogs:\n%s", functionName, execution.attemptsNote(), execution.Error, strings.Join(execution.Logs, "\n")),
+		)
+		return
+	}
+}
+
+// executes the configured function and stores the outcome in the model
+func (r *appFunctionInvocationResource) invoke(ctx context.Context, model *appFunctionInvocationResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	functionArgs := make([]string, 0, len(model.FunctionArgs.Elements()))
+	diags.Append(model.FunctionArgs.ElementsAs(ctx, &functionArgs, false)...)
+	functionArgsJSON := make([]string, 0, len(model.FunctionArgsJSON.Elements()))
+	diags.Append(model.FunctionArgsJSON.ElementsAs(ctx, &functionArgsJSON, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	arguments, err := appFunctionArguments(functionArgs, functionArgsJSON)
+	if err != nil {
+		diags.AddError("Invalid MongoDB Atlas App Services Function Arguments", err.Error())
+		return diags
+	}
+	retryPolicy, policyDiags := appFunctionRetryPolicyFromConfig(ctx, model.MaxAttempts, model.RetryOn, model.RetryDelay)
+	diags.Append(policyDiags...)
+	if diags.HasError() {
+		return diags
+	}
+
+	functionName := model.FunctionName.ValueString()
+	execution, err := executeAppServicesFunctionWithRetry(r.bearer_token, model.ProjectID.ValueString(), model.AppServicesAppID.ValueString(), functionName, arguments, model.ExecutionTimeout.ValueInt64(), "", retryPolicy)
+	if err != nil {
+		diags.AddError("Unable to Execute MongoDB Atlas App Services Function", err.Error())
+		return diags
+	}
+	if execution.Error != "" && !model.IgnoreErrors.ValueBool() {
+		diags.AddError(
+			"MongoDB Atlas App Services Function Reported an Error",
+			fmt.Sprintf("app function %s reported an error%s: %s\n\nLogs:\n%s", functionName, execution.attemptsNote(), execution.Error, strings.Join(execution.Logs, "\n")),
+		)
+		return diags
+	}
+
+	expectations := make([]appFunctionExpectModel, 0, len(model.Expect.Elements()))
+	diags.Append(model.Expect.ElementsAs(ctx, &expectations, false)...)
+	diags.Append(checkAppFunctionExpectations(functionName, execution.ResultJSON, expectations, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	logs, listDiags := types.ListValueFrom(ctx, types.StringType, execution.Logs)
+	diags.Append(listDiags...)
+	if diags.HasError() {
+		return diags
+	}
+	model.LastRun = types.StringValue(time.Now().UTC().Format(time.RFC3339))
+	model.ResultJSON = types.StringValue(execution.ResultJSON)
+	model.Logs = logs
+	model.Error = types.StringValue(execution.Error)
+	return diags
+}
@@ -0,0 +1,54 @@
+package pgrmongodb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// note this checks for functions named myfunction and mycleanupfunction in the app
+func TestAccPGRMongoDBAppFunctionInvocation(t *testing.T) {
+	project_id := "000000000000000000000000"
+	appservices_app_id := "000000000000000000000000"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccCheckPGRMongoDBAppFunctionInvocationConfig(project_id, appservices_app_id, "1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("pgrmongodb_appfunction_invocation.test", "id"),
+					resource.TestCheckResourceAttrSet("pgrmongodb_appfunction_invocation.test", "last_run"),
+					resource.TestCheckResourceAttrSet("pgrmongodb_appfunction_invocation.test", "result_json"),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunction_invocation.test", "triggers.version", "1"),
+				),
+			},
+			// Update triggers and Read testing
+			{
+				Config: providerConfig + testAccCheckPGRMongoDBAppFunctionInvocationConfig(project_id, appservices_app_id, "2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("pgrmongodb_appfunction_invocation.test", "last_run"),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunction_invocation.test", "triggers.version", "2"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccCheckPGRMongoDBAppFunctionInvocationConfig(project_id string, appservices_app_id string, version string) string {
+	return fmt.Sprintf(`
+		resource "pgrmongodb_appfunction_invocation" "test" {
+			project_id = "%s"
+			appservices_app_id = "%s"
+			function_name = "myfunction"
+			function_args_json = [jsonencode("tfarg1"), jsonencode(42)]
+			triggers = {
+				version = "%s"
+			}
+			destroy_function_name = "mycleanupfunction"
+		}
+		`, project_id, appservices_app_id, version)
+}
@@ -0,0 +1,673 @@
+package pgrmongodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &appFunctionsResource{}
+	_ resource.ResourceWithConfigure      = &appFunctionsResource{}
+	_ resource.ResourceWithImportState    = &appFunctionsResource{}
+	_ resource.ResourceWithValidateConfig = &appFunctionsResource{}
+	_ resource.ResourceWithModifyPlan     = &appFunctionsResource{}
+)
+
+// private state key holding the last_modified timestamp of every managed function, so a refresh only fetches the
+// code of functions that changed since the last read
+const appFunctionsLastModifiedKey = "function_last_modified"
+
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+func NewAppFunctionsResource() resource.Resource {
+	return &appFunctionsResource{}
+}
+
+type appFunctionsResource struct {
+	bearer_token string
+}
+
+type appFunctionsResourceModel struct {
+	ID                 types.String `tfsdk:"id"`
+	ProjectID          types.String `tfsdk:"project_id"`
+	AppServicesAppID   types.String `tfsdk:"appservices_app_id"`
+	SourceDir          types.String `tfsdk:"source_dir"`
+	Functions          types.Map    `tfsdk:"functions"`
+	PurgeUnmanaged     types.Bool   `tfsdk:"purge_unmanaged"`
+	AdoptExisting      types.Bool   `tfsdk:"adopt_existing"`
+	FunctionIDs        types.Map    `tfsdk:"function_ids"`
+	UnmanagedFunctions types.Set    `tfsdk:"unmanaged_functions"`
+}
+
+func (r *appFunctionsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_appfunctions"
+}
+
+func (r *appFunctionsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the set of MongoDB Atlas App Services Functions in an app",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "identifier for resource.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				Description: "MongoDB Atlas project identifier. Sometime referred to as group id.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal project_id",
+					),
+				},
+			},
+			"appservices_app_id": schema.StringAttribute{
+				Description: "MongoDB Atlas App Services app id to manage functions.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal appservices_app_id",
+					),
+				},
+			},
+			"source_dir": schema.StringAttribute{
+				Description: "Directory of functions to deploy to App Services. Each .js file is deployed as a function named after the file without its extension. Exactly one of source_dir or functions must be set.",
+				Optional:    true,
+			},
+			"functions": schema.MapAttribute{
+				Description: "Map of function name to code to deploy to App Services. Populated from source_dir when source_dir is set. Exactly one of source_dir or functions must be set.",
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.ConflictsWith(path.MatchRoot("source_dir")),
+				},
+			},
+			"purge_unmanaged": schema.BoolAttribute{
+				Description: "Delete functions in the app that are not part of this set. Defaults to false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Description: "Take over functions that already exist in the app under a name of this set and overwrite their code. Without it, or purge_unmanaged, such a function is reported as an error. Defaults to false.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"function_ids": schema.MapAttribute{
+				Description: "Map of function name to App Services function id.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"unmanaged_functions": schema.SetAttribute{
+				Description: "Names of functions in the app that are not part of this set.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (r *appFunctionsResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.bearer_token = req.ProviderData.(providerData).bearer_token
+}
+
+func (r *appFunctionsResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config appFunctionsResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.SourceDir.IsUnknown() || config.Functions.IsUnknown() {
+		return
+	}
+	if config.SourceDir.IsNull() && config.Functions.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("source_dir"),
+			"Missing App Services Functions",
+			"Exactly one of source_dir or functions must be set.",
+		)
+		return
+	}
+
+	functions := make(map[string]string)
+	if !config.SourceDir.IsNull() {
+		var err error
+		functions, err = readAppFunctionsDir(config.SourceDir.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("source_dir"),
+				"Unable to Read App Services Functions Directory",
+				"Could not read source_dir. Received error: "+err.Error(),
+			)
+			return
+		}
+	} else {
+		for name, value := range config.Functions.Elements() {
+			functionCode, ok := value.(types.String)
+			if !ok || functionCode.IsUnknown() || functionCode.IsNull() {
+				continue
+			}
+			functions[name] = functionCode.ValueString()
+		}
+	}
+
+	for name, functionCode := range functions {
+		_, parseErr := parseAppFunctionCode(functionCode)
+		if parseErr != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("functions").AtMapKey(name),
+				"Invalid App Services Function Code",
+				fmt.Sprintf("function %s contains a JavaScript syntax error on line %d, column %d: %s\n\n%s", name, parseErr.Line, parseErr.Column, parseErr.Message, parseErr.Context),
+			)
+		}
+	}
+}
+
+func (r *appFunctionsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan appFunctionsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.SourceDir.IsNull() && !plan.SourceDir.IsUnknown() {
+		functions, err := readAppFunctionsDir(plan.SourceDir.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("source_dir"),
+				"Unable to Read App Services Functions Directory",
+				"Could not read source_dir. Received error: "+err.Error(),
+			)
+			return
+		}
+		plan.Functions, diags = types.MapValueFrom(ctx, types.StringType, functions)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// function ids and unmanaged functions are only known ahead of apply when every planned function already exists
+	plan.FunctionIDs = types.MapUnknown(types.StringType)
+	plan.UnmanagedFunctions = types.SetUnknown(types.StringType)
+	if !req.State.Raw.IsNull() && !plan.Functions.IsUnknown() {
+		var state appFunctionsResourceModel
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		stateIDs := make(map[string]string)
+		diags = state.FunctionIDs.ElementsAs(ctx, &stateIDs, false)
+		resp.Diagnostics.Append(diags...)
+		stateUnmanaged := make([]string, 0, len(state.UnmanagedFunctions.Elements()))
+		diags = state.UnmanagedFunctions.ElementsAs(ctx, &stateUnmanaged, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plannedIDs := make(map[string]string)
+		allKnown := true
+		for name := range plan.Functions.Elements() {
+			id, ok := stateIDs[name]
+			if !ok {
+				allKnown = false
+				break
+			}
+			plannedIDs[name] = id
+		}
+		if allKnown {
+			plan.FunctionIDs, diags = types.MapValueFrom(ctx, types.StringType, plannedIDs)
+			resp.Diagnostics.Append(diags...)
+		}
+
+		plannedUnmanaged := make([]string, 0, len(stateUnmanaged))
+		if !plan.PurgeUnmanaged.ValueBool() {
+			for _, name := range stateUnmanaged {
+				if _, ok := plan.Functions.Elements()[name]; !ok {
+					plannedUnmanaged = append(plannedUnmanaged, name)
+				}
+			}
+		}
+		plan.UnmanagedFunctions, diags = types.SetValueFrom(ctx, types.StringType, plannedUnmanaged)
+		resp.Diagnostics.Append(diags...)
+	} else if plan.PurgeUnmanaged.ValueBool() {
+		plan.UnmanagedFunctions = types.SetValueMust(types.StringType, nil)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.Plan.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *appFunctionsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan appFunctionsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired := make(map[string]string)
+	diags = plan.Functions.ElementsAs(ctx, &desired, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "creating mongodb atlas app services functions")
+	functionIDs, lastModified, unmanaged, err := r.syncFunctions(ctx, plan.ProjectID.ValueString(), plan.AppServicesAppID.ValueString(), desired, map[string]string{}, plan.PurgeUnmanaged.ValueBool(), plan.PurgeUnmanaged.ValueBool() || plan.AdoptExisting.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating App Services Functions",
+			"Could not create MongoDB Atlas App Services Functions. Received error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = plan.AppServicesAppID
+	plan.FunctionIDs, diags = types.MapValueFrom(ctx, types.StringType, functionIDs)
+	resp.Diagnostics.Append(diags...)
+	plan.UnmanagedFunctions, diags = types.SetValueFrom(ctx, types.StringType, unmanaged)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setAppFunctionsLastModified(ctx, resp.Private, lastModified)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *appFunctionsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state appFunctionsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed := make(map[string]string)
+	diags = state.Functions.ElementsAs(ctx, &managed, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	lastModified, diags := getAppFunctionsLastModified(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "reading mongodb atlas app services functions")
+	functions, functionIDs, lastModified, unmanaged, err := readAppServicesFunctionSet(r.bearer_token, state.ProjectID.ValueString(), state.AppServicesAppID.ValueString(), managed, lastModified)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading App Services Functions",
+			"Could not read MongoDB Atlas App Services Functions. Received error: "+err.Error(),
+		)
+		return
+	}
+
+	state.ID = state.AppServicesAppID
+	state.Functions, diags = types.MapValueFrom(ctx, types.StringType, functions)
+	resp.Diagnostics.Append(diags...)
+	state.FunctionIDs, diags = types.MapValueFrom(ctx, types.StringType, functionIDs)
+	resp.Diagnostics.Append(diags...)
+	state.UnmanagedFunctions, diags = types.SetValueFrom(ctx, types.StringType, unmanaged)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setAppFunctionsLastModified(ctx, resp.Private, lastModified)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *appFunctionsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var state appFunctionsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan appFunctionsResourceModel
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired := make(map[string]string)
+	diags = plan.Functions.ElementsAs(ctx, &desired, false)
+	resp.Diagnostics.Append(diags...)
+	previous := make(map[string]string)
+	diags = state.Functions.ElementsAs(ctx, &previous, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "updating mongodb atlas app services functions")
+	functionIDs, lastModified, unmanaged, err := r.syncFunctions(ctx, plan.ProjectID.ValueString(), plan.AppServicesAppID.ValueString(), desired, previous, plan.PurgeUnmanaged.ValueBool(), plan.PurgeUnmanaged.ValueBool() || plan.AdoptExisting.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating App Services Functions",
+			"Could not update MongoDB Atlas App Services Functions. Received error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = plan.AppServicesAppID
+	plan.FunctionIDs, diags = types.MapValueFrom(ctx, types.StringType, functionIDs)
+	resp.Diagnostics.Append(diags...)
+	plan.UnmanagedFunctions, diags = types.SetValueFrom(ctx, types.StringType, unmanaged)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(setAppFunctionsLastModified(ctx, resp.Private, lastModified)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *appFunctionsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state appFunctionsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	functionIDs := make(map[string]string)
+	diags = state.FunctionIDs.ElementsAs(ctx, &functionIDs, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "deleting mongodb atlas app services functions")
+	for name, functionID := range functionIDs {
+		tflog.Debug(ctx, fmt.Sprintf("deleting function %s", name))
+		err := deleteAppServicesFunction(r.bearer_token, state.ProjectID.ValueString(), state.AppServicesAppID.ValueString(), functionID)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error Deleting App Services Functions",
+				"Could not delete MongoDB Atlas App Services Function "+name+". Received error: "+err.Error(),
+			)
+			return
+		}
+	}
+}
+
+func (r *appFunctionsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+
+	if len(idParts) != 2 {
+		resp.Diagnostics.AddError(
+			"Error Importing App Services Functions",
+			"Could not import MongoDB Atlas App Services Functions.\nPlease ensure you run terraform import with project_id,appservices_app_id",
+		)
+		return
+	}
+
+	// every function in the app is imported as managed
+	functions, functionIDs, lastModified, _, err := readAppServicesFunctionSet(r.bearer_token, idParts[0], idParts[1], nil, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Importing App Services Functions",
+			"Unable to get app services functions for subsequent import. got error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("appservices_app_id"), idParts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("functions"), functions)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("function_ids"), functionIDs)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("purge_unmanaged"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("adopt_existing"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("unmanaged_functions"), []string{})...)
+	resp.Diagnostics.Append(setAppFunctionsLastModified(ctx, resp.Private, lastModified)...)
+}
+
+// creates, updates and deletes functions so the app matches desired. previous holds the function code from state
+// so unchanged functions are not redeployed. a live function that is not in previous is only overwritten when
+// adoptExisting is set. returns the function ids and last_modified timestamps by name and the names of unmanaged functions
+func (r *appFunctionsResource) syncFunctions(ctx context.Context, projectID string, appServicesAppID string, desired map[string]string, previous map[string]string, purgeUnmanaged bool, adoptExisting bool) (map[string]string, map[string]float64, []string, error) {
+	live, err := listAppServicesFunctions(r.bearer_token, projectID, appServicesAppID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	liveIDs, _, err := appServicesFunctionSummaries(live)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// refuse to overwrite functions this resource never managed before anything is changed
+	conflicting := make([]string, 0)
+	for _, name := range names {
+		if _, exists := liveIDs[name]; !exists {
+			continue
+		}
+		if _, wasManaged := previous[name]; !wasManaged && !adoptExisting {
+			conflicting = append(conflicting, name)
+		}
+	}
+	if len(conflicting) > 0 {
+		return nil, nil, nil, fmt.Errorf("functions %s already exist in the app and are not managed by this resource. Set adopt_existing or purge_unmanaged to overwrite them", strings.Join(conflicting, ", "))
+	}
+
+	functionIDs := make(map[string]string, len(desired))
+	for _, name := range names {
+		functionID, exists := liveIDs[name]
+		if !exists {
+			tflog.Debug(ctx, fmt.Sprintf("creating function %s", name))
+			functionID, err = createAppServicesFunction(r.bearer_token, projectID, appServicesAppID, name, desired[name])
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		} else if previousCode, ok := previous[name]; !ok || previousCode != desired[name] {
+			tflog.Debug(ctx, fmt.Sprintf("updating function %s", name))
+			err = updateAppServicesFunction(r.bearer_token, projectID, appServicesAppID, functionID, name, desired[name])
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		functionIDs[name] = functionID
+	}
+
+	unmanaged := make([]string, 0)
+	for name, functionID := range liveIDs {
+		if _, ok := desired[name]; ok {
+			continue
+		}
+		if _, wasManaged := previous[name]; wasManaged || purgeUnmanaged {
+			tflog.Debug(ctx, fmt.Sprintf("deleting function %s", name))
+			err = deleteAppServicesFunction(r.bearer_token, projectID, appServicesAppID, functionID)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			continue
+		}
+		unmanaged = append(unmanaged, name)
+	}
+	sort.Strings(unmanaged)
+
+	// list once more to record the timestamps of the deployed code for the next refresh
+	live, err = listAppServicesFunctions(r.bearer_token, projectID, appServicesAppID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	_, liveLastModified, err := appServicesFunctionSummaries(live)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	lastModified := make(map[string]float64, len(functionIDs))
+	for name := range functionIDs {
+		if v, ok := liveLastModified[name]; ok {
+			lastModified[name] = v
+		}
+	}
+
+	return functionIDs, lastModified, unmanaged, nil
+}
+
+// reads the code and ids of the managed functions that still exist in the app along with the names of every other
+// function. a nil managed map treats every function in the app as managed. the code of a managed function is only
+// fetched when its last_modified timestamp differs from the one in lastModified, otherwise the code from managed is kept
+func readAppServicesFunctionSet(bearer_token string, projectID string, appServicesAppID string, managed map[string]string, lastModified map[string]float64) (map[string]string, map[string]string, map[string]float64, []string, error) {
+	live, err := listAppServicesFunctions(bearer_token, projectID, appServicesAppID)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	liveIDs, liveLastModified, err := appServicesFunctionSummaries(live)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	functions := make(map[string]string)
+	functionIDs := make(map[string]string)
+	readLastModified := make(map[string]float64)
+	unmanaged := make([]string, 0)
+	for name, functionID := range liveIDs {
+		managedCode, ok := managed[name]
+		if managed != nil && !ok {
+			unmanaged = append(unmanaged, name)
+			continue
+		}
+		functionIDs[name] = functionID
+
+		current, hasCurrent := liveLastModified[name]
+		if hasCurrent {
+			readLastModified[name] = current
+			if previous, hasPrevious := lastModified[name]; ok && hasPrevious && previous == current {
+				functions[name] = managedCode
+				continue
+			}
+		}
+		_, functionCode, err := getAppServicesFunctionByID(bearer_token, projectID, appServicesAppID, functionID)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		functions[name] = functionCode
+	}
+	sort.Strings(unmanaged)
+
+	return functions, functionIDs, readLastModified, unmanaged, nil
+}
+
+// maps the entries of a function list response to ids and last_modified timestamps by name
+func appServicesFunctionSummaries(live []map[string]interface{}) (map[string]string, map[string]float64, error) {
+	ids := make(map[string]string, len(live))
+	lastModified := make(map[string]float64, len(live))
+	for _, v := range live {
+		name, nameOk := v["name"].(string)
+		functionID, idOk := v["_id"].(string)
+		if !nameOk || !idOk {
+			return nil, nil, fmt.Errorf("app services returned a function without an _id or name")
+		}
+		ids[name] = functionID
+		if timestamp, ok := v["last_modified"].(float64); ok {
+			lastModified[name] = timestamp
+		}
+	}
+	return ids, lastModified, nil
+}
+
+func getAppFunctionsLastModified(ctx context.Context, private privateStateGetter) (map[string]float64, diag.Diagnostics) {
+	lastModified := make(map[string]float64)
+	value, diags := private.GetKey(ctx, appFunctionsLastModifiedKey)
+	if diags.HasError() || len(value) == 0 {
+		return lastModified, diags
+	}
+	// a broken value only costs a full read
+	if err := json.Unmarshal(value, &lastModified); err != nil {
+		return make(map[string]float64), diags
+	}
+	return lastModified, diags
+}
+
+func setAppFunctionsLastModified(ctx context.Context, private privateStateSetter, lastModified map[string]float64) diag.Diagnostics {
+	value, err := json.Marshal(lastModified)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Unable to Store App Services Function Timestamps", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, appFunctionsLastModifiedKey, value)
+}
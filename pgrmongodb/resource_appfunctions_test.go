@@ -0,0 +1,93 @@
+package pgrmongodb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccPGRMongoDBAppFunctions(t *testing.T) {
+	project_id := "000000000000000000000000"
+	appservices_app_id := "000000000000000000000000"
+
+	sourceDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sourceDir, "my_tf_function_a.js"), []byte(function_2), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sourceDir, "my_tf_function_c.js"), []byte(function_1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + fmt.Sprintf(`
+		resource "pgrmongodb_appfunctions" "test" {
+			project_id = "%s"
+			appservices_app_id = "%s"
+			functions = {
+				my_tf_function_a = <<EOT
+%sEOT
+				my_tf_function_b = <<EOT
+%sEOT
+			}
+		}
+		`, project_id, appservices_app_id, function_1, function_1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctions.test", "functions.%", "2"),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctions.test", "functions.my_tf_function_a", function_1),
+					resource.TestCheckResourceAttrSet("pgrmongodb_appfunctions.test", "function_ids.my_tf_function_a"),
+					resource.TestCheckResourceAttrSet("pgrmongodb_appfunctions.test", "function_ids.my_tf_function_b"),
+				),
+			},
+			// Update from a directory and Read testing
+			{
+				Config: providerConfig + fmt.Sprintf(`
+		resource "pgrmongodb_appfunctions" "test" {
+			project_id = "%s"
+			appservices_app_id = "%s"
+			source_dir = "%s"
+		}
+		`, project_id, appservices_app_id, filepath.ToSlash(sourceDir)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctions.test", "functions.%", "2"),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctions.test", "functions.my_tf_function_a", function_2),
+					resource.TestCheckResourceAttr("pgrmongodb_appfunctions.test", "functions.my_tf_function_c", function_1),
+					resource.TestCheckNoResourceAttr("pgrmongodb_appfunctions.test", "function_ids.my_tf_function_b"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+// note this expects a function named myfunction in the app that is not managed by terraform
+func TestAccPGRMongoDBAppFunctionsExisting(t *testing.T) {
+	project_id := "000000000000000000000000"
+	appservices_app_id := "000000000000000000000000"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + fmt.Sprintf(`
+		resource "pgrmongodb_appfunctions" "test" {
+			project_id = "%s"
+			appservices_app_id = "%s"
+			functions = {
+				myfunction = <<EOT
+%sEOT
+			}
+		}
+		`, project_id, appservices_app_id, function_1),
+				ExpectError: regexp.MustCompile("functions myfunction already exist in the app"),
+			},
+		},
+	})
+}
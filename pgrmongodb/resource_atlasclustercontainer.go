@@ -0,0 +1,449 @@
+package pgrmongodb
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &atlasClusterContainerResource{}
+	_ resource.ResourceWithConfigure      = &atlasClusterContainerResource{}
+	_ resource.ResourceWithImportState    = &atlasClusterContainerResource{}
+	_ resource.ResourceWithValidateConfig = &atlasClusterContainerResource{}
+)
+
+// smallest and largest CIDR prefix length Atlas accepts for a container per cloud provider
+var clusterContainerCIDRPrefixLengths = map[string][2]int{
+	"AWS":   {21, 24},
+	"AZURE": {16, 24},
+	"GCP":   {16, 18},
+}
+
+var rfc1918Prefixes = []netip.Prefix{
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+}
+
+func NewAtlasClusterContainerResource() resource.Resource {
+	return &atlasClusterContainerResource{}
+}
+
+type atlasClusterContainerResource struct {
+	public_key  string
+	private_key string
+}
+
+type atlasClusterContainerResourceModel struct {
+	ID                  types.String `tfsdk:"id"`
+	ProjectID           types.String `tfsdk:"project_id"`
+	CloudProvider       types.String `tfsdk:"cloud_provider"`
+	AtlasCIDRBlock      types.String `tfsdk:"atlas_cidr_block"`
+	Region              types.String `tfsdk:"region"`
+	Regions             types.List   `tfsdk:"regions"`
+	Provisioned         types.Bool   `tfsdk:"provisioned"`
+	VpcID               types.String `tfsdk:"vpc_id"`
+	AzureSubscriptionID types.String `tfsdk:"azure_subscription_id"`
+	VnetName            types.String `tfsdk:"vnet_name"`
+	GCPProjectID        types.String `tfsdk:"gcp_project_id"`
+	NetworkName         types.String `tfsdk:"network_name"`
+}
+
+func (r *atlasClusterContainerResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_atlasclustercontainer"
+}
+
+func (r *atlasClusterContainerResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a MongoDB Atlas Cluster network container",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the container.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				Description: "MongoDB Atlas project identifier. Sometime referred to as group id.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal project_id",
+					),
+				},
+			},
+			"cloud_provider": schema.StringAttribute{
+				Description: "Cloud provider of the container.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"AWS", "GCP", "AZURE"}...),
+				},
+			},
+			"atlas_cidr_block": schema.StringAttribute{
+				Description: "RFC 1918 CIDR block of the Atlas VPC or VNet. AWS accepts /21 to /24, Azure /16 to /24 and GCP /16 to /18. It can only be changed while no cluster is deployed into the container.",
+				Required:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Atlas region name of the container, for example US_EAST_1. Required for AWS and Azure, not used for GCP.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`),
+						"must be an Atlas region name such as US_EAST_1",
+					),
+				},
+			},
+			"regions": schema.ListAttribute{
+				Description: "Atlas region names the GCP container serves. The container is global when not set, and removing regions makes it global again. Only used for GCP.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(
+							regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`),
+							"must be an Atlas region name such as CENTRAL_US",
+						),
+					),
+				},
+			},
+			"provisioned": schema.BoolAttribute{
+				Description: "Whether Atlas has deployed clusters into the container.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"vpc_id": schema.StringAttribute{
+				Description: "AWS VPC id of the Atlas VPC. Only set for AWS.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"azure_subscription_id": schema.StringAttribute{
+				Description: "Azure subscription id of the Atlas VNet. Only set for Azure.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"vnet_name": schema.StringAttribute{
+				Description: "Name of the Atlas VNet. Only set for Azure.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"gcp_project_id": schema.StringAttribute{
+				Description: "GCP project id of the Atlas VPC. Only set for GCP.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"network_name": schema.StringAttribute{
+				Description: "GCP network name of the Atlas VPC. Only set for GCP.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *atlasClusterContainerResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.public_key = req.ProviderData.(providerData).public_key
+	r.private_key = req.ProviderData.(providerData).private_key
+}
+
+func (r *atlasClusterContainerResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config atlasClusterContainerResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || config.CloudProvider.IsUnknown() {
+		return
+	}
+
+	cloudProvider := config.CloudProvider.ValueString()
+	if cloudProvider == "GCP" {
+		if !config.Region.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("region"),
+				"Invalid Atlas Cluster Container Region",
+				"GCP containers are global, use regions to limit the regions they serve.",
+			)
+		}
+	} else {
+		if config.Region.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("region"),
+				"Missing Atlas Cluster Container Region",
+				fmt.Sprintf("region is required for %s containers.", cloudProvider),
+			)
+		}
+		if !config.Regions.IsNull() && !config.Regions.IsUnknown() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("regions"),
+				"Invalid Atlas Cluster Container Regions",
+				"regions is only used for GCP containers, use region instead.",
+			)
+		}
+	}
+
+	if !config.AtlasCIDRBlock.IsNull() && !config.AtlasCIDRBlock.IsUnknown() {
+		if err := validateClusterContainerCIDR(cloudProvider, config.AtlasCIDRBlock.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("atlas_cidr_block"),
+				"Invalid Atlas Cluster Container CIDR Block",
+				err.Error(),
+			)
+		}
+	}
+}
+
+func (r *atlasClusterContainerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan atlasClusterContainerResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	container, diags := plan.clusterContainer(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "creating mongodb atlas cluster network container")
+	container, err := saveClusterContainer(r.public_key, r.private_key, plan.ProjectID.ValueString(), "", container)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating Atlas Cluster Container",
+			"Could not create MongoDB Atlas Cluster network container. Received error: "+err.Error(),
+		)
+		return
+	}
+
+	diags = plan.fromClusterContainer(ctx, container)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *atlasClusterContainerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state atlasClusterContainerResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "reading mongodb atlas cluster network container")
+	container, err := getClusterContainer(r.public_key, r.private_key, state.ProjectID.ValueString(), state.ID.ValueString())
+	if isDigestNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Atlas Cluster Container",
+			"Could not read MongoDB Atlas Cluster network container. Received error: "+err.Error(),
+		)
+		return
+	}
+
+	diags = state.fromClusterContainer(ctx, container)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *atlasClusterContainerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan atlasClusterContainerResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	container, diags := plan.clusterContainer(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("updating mongodb atlas cluster network container %s", plan.ID.ValueString()))
+	container, err := saveClusterContainer(r.public_key, r.private_key, plan.ProjectID.ValueString(), plan.ID.ValueString(), container)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating Atlas Cluster Container",
+			"Could not update MongoDB Atlas Cluster network container. Received error: "+err.Error(),
+		)
+		return
+	}
+
+	diags = plan.fromClusterContainer(ctx, container)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *atlasClusterContainerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state atlasClusterContainerResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("deleting mongodb atlas cluster network container %s", state.ID.ValueString()))
+	err := deleteClusterContainer(r.public_key, r.private_key, state.ProjectID.ValueString(), state.ID.ValueString())
+	if err != nil && !isDigestNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Atlas Cluster Container",
+			"Could not delete MongoDB Atlas Cluster network container. Atlas only deletes containers without clusters or peering connections. Received error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *atlasClusterContainerResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Error Importing Atlas Cluster Container",
+			"Could not import MongoDB Atlas Cluster network container.\nPlease ensure you run terraform import with project_id,container_id",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+}
+
+// returns the container described by the plan
+func (m atlasClusterContainerResourceModel) clusterContainer(ctx context.Context) (clusterContainer, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	container := clusterContainer{
+		ProviderName:   m.CloudProvider.ValueString(),
+		AtlasCIDRBlock: m.AtlasCIDRBlock.ValueString(),
+	}
+	if container.ProviderName == "GCP" {
+		// an empty list is sent when regions is not set, so a container whose regions were removed becomes global
+		container.Regions = make([]string, 0, len(m.Regions.Elements()))
+		if !m.Regions.IsNull() && !m.Regions.IsUnknown() {
+			diags = m.Regions.ElementsAs(ctx, &container.Regions, false)
+		}
+	} else {
+		container.Regions = []string{m.Region.ValueString()}
+	}
+	return container, diags
+}
+
+// copies the attributes Atlas reports for container into the model
+func (m *atlasClusterContainerResourceModel) fromClusterContainer(ctx context.Context, container clusterContainer) diag.Diagnostics {
+	var diags diag.Diagnostics
+	m.ID = types.StringValue(container.ID)
+	m.CloudProvider = types.StringValue(container.ProviderName)
+	m.AtlasCIDRBlock = types.StringValue(container.AtlasCIDRBlock)
+	m.Provisioned = types.BoolValue(container.Provisioned)
+	m.VpcID = optionalStringValue(container.VpcID)
+	m.AzureSubscriptionID = optionalStringValue(container.AzureSubscriptionID)
+	m.VnetName = optionalStringValue(container.VnetName)
+	m.GCPProjectID = optionalStringValue(container.GCPProjectID)
+	m.NetworkName = optionalStringValue(container.NetworkName)
+	if container.ProviderName == "GCP" {
+		m.Region = types.StringNull()
+		// a global container keeps regions null, or an empty list when the configuration sets regions = []
+		if len(container.Regions) > 0 {
+			m.Regions, diags = types.ListValueFrom(ctx, types.StringType, container.Regions)
+		} else if m.Regions.IsUnknown() || len(m.Regions.Elements()) > 0 {
+			m.Regions = types.ListNull(types.StringType)
+		}
+	} else {
+		m.Region = types.StringValue(container.Regions[0])
+		m.Regions = types.ListNull(types.StringType)
+	}
+	return diags
+}
+
+// checks that cidr is an RFC 1918 network whose size the cloud provider accepts for a container
+func validateClusterContainerCIDR(cloudProvider string, cidr string) error {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil || !prefix.Addr().Is4() {
+		return fmt.Errorf("%s is not an IPv4 CIDR block such as 10.8.0.0/21", cidr)
+	}
+	if prefix.Masked() != prefix {
+		return fmt.Errorf("%s is not a network address, use %s", cidr, prefix.Masked())
+	}
+	private := false
+	for _, v := range rfc1918Prefixes {
+		if v.Contains(prefix.Addr()) && prefix.Bits() >= v.Bits() {
+			private = true
+		}
+	}
+	if !private {
+		return fmt.Errorf("%s is not within the RFC 1918 private ranges 10.0.0.0/8, 172.16.0.0/12 or 192.168.0.0/16", cidr)
+	}
+	if lengths, ok := clusterContainerCIDRPrefixLengths[cloudProvider]; ok && (prefix.Bits() < lengths[0] || prefix.Bits() > lengths[1]) {
+		return fmt.Errorf("%s containers need a CIDR block between /%d and /%d, got /%d", cloudProvider, lengths[0], lengths[1], prefix.Bits())
+	}
+	return nil
+}
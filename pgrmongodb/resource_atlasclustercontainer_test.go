@@ -0,0 +1,158 @@
+package pgrmongodb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccPGRMongoDBAtlasClusterContainerResource(t *testing.T) {
+	project_id := "000000000000000000000000"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccCheckPGRMongoDBAtlasClusterContainerConfig(project_id, "10.8.0.0/21"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("pgrmongodb_atlasclustercontainer.test", "id"),
+					resource.TestCheckResourceAttr("pgrmongodb_atlasclustercontainer.test", "atlas_cidr_block", "10.8.0.0/21"),
+					resource.TestCheckResourceAttr("pgrmongodb_atlasclustercontainer.test", "region", "US_WEST_2"),
+					resource.TestCheckResourceAttr("pgrmongodb_atlasclustercontainer.test", "provisioned", "false"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "pgrmongodb_atlasclustercontainer.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return project_id + "," + s.RootModule().Resources["pgrmongodb_atlasclustercontainer.test"].Primary.ID, nil
+				},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccCheckPGRMongoDBAtlasClusterContainerConfig(project_id, "10.8.0.0/22"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_atlasclustercontainer.test", "atlas_cidr_block", "10.8.0.0/22"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+// note this expects no GCP network container in the project yet, Atlas allows one per project
+func TestAccPGRMongoDBAtlasClusterContainerResourceGCPRegions(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_atlasclustercontainer" "test" {
+	project_id = "000000000000000000000000"
+	cloud_provider = "GCP"
+	atlas_cidr_block = "10.16.0.0/18"
+	regions = ["CENTRAL_US"]
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_atlasclustercontainer.test", "regions.#", "1"),
+					resource.TestCheckResourceAttr("pgrmongodb_atlasclustercontainer.test", "regions.0", "CENTRAL_US"),
+				),
+			},
+			// removing regions makes the container global again
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_atlasclustercontainer" "test" {
+	project_id = "000000000000000000000000"
+	cloud_provider = "GCP"
+	atlas_cidr_block = "10.16.0.0/18"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("pgrmongodb_atlasclustercontainer.test", "regions"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPGRMongoDBAtlasClusterContainerResourceInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      providerConfig + testAccCheckPGRMongoDBAtlasClusterContainerConfig("000000000000000000000000", "10.8.0.0/16"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`AWS containers need a CIDR block between /21 and /24`),
+			},
+			{
+				Config:      providerConfig + testAccCheckPGRMongoDBAtlasClusterContainerConfig("000000000000000000000000", "8.8.0.0/21"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`RFC 1918`),
+			},
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_atlasclustercontainer" "test" {
+	project_id = "000000000000000000000000"
+	cloud_provider = "GCP"
+	atlas_cidr_block = "10.16.0.0/18"
+	region = "CENTRAL_US"
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Atlas Cluster Container Region`),
+			},
+		},
+	})
+}
+
+func testAccCheckPGRMongoDBAtlasClusterContainerConfig(project_id string, atlas_cidr_block string) string {
+	return fmt.Sprintf(`
+		resource "pgrmongodb_atlasclustercontainer" "test" {
+			project_id = "%s"
+			cloud_provider = "AWS"
+			atlas_cidr_block = "%s"
+			region = "US_WEST_2"
+		}
+		`, project_id, atlas_cidr_block)
+}
+
+func TestValidateClusterContainerCIDR(t *testing.T) {
+	tests := []struct {
+		cloudProvider string
+		cidr          string
+		wantErr       string
+	}{
+		{cloudProvider: "AWS", cidr: "10.8.0.0/21"},
+		{cloudProvider: "AWS", cidr: "192.168.248.0/24"},
+		{cloudProvider: "AZURE", cidr: "172.16.0.0/16"},
+		{cloudProvider: "GCP", cidr: "10.16.0.0/18"},
+		{cloudProvider: "AWS", cidr: "10.8.0.0/16", wantErr: "AWS containers need a CIDR block between /21 and /24, got /16"},
+		{cloudProvider: "AZURE", cidr: "10.8.0.0/25", wantErr: "AZURE containers need a CIDR block between /16 and /24, got /25"},
+		{cloudProvider: "GCP", cidr: "10.16.0.0/21", wantErr: "GCP containers need a CIDR block between /16 and /18, got /21"},
+		{cloudProvider: "AWS", cidr: "8.8.0.0/21", wantErr: "RFC 1918"},
+		{cloudProvider: "AZURE", cidr: "172.32.0.0/16", wantErr: "RFC 1918"},
+		{cloudProvider: "GCP", cidr: "10.0.0.0/7", wantErr: "RFC 1918"},
+		{cloudProvider: "AWS", cidr: "10.8.0.1/21", wantErr: "is not a network address, use 10.8.0.0/21"},
+		{cloudProvider: "AWS", cidr: "10.8.0.0", wantErr: "is not an IPv4 CIDR block"},
+		{cloudProvider: "AWS", cidr: "fd00::/48", wantErr: "is not an IPv4 CIDR block"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cloudProvider+" "+tt.cidr, func(t *testing.T) {
+			err := validateClusterContainerCIDR(tt.cloudProvider, tt.cidr)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,514 @@
+package pgrmongodb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                   = &networkPeeringResource{}
+	_ resource.ResourceWithConfigure      = &networkPeeringResource{}
+	_ resource.ResourceWithImportState    = &networkPeeringResource{}
+	_ resource.ResourceWithValidateConfig = &networkPeeringResource{}
+)
+
+const networkPeeringDefaultTimeout = 30 * time.Minute
+
+func NewNetworkPeeringResource() resource.Resource {
+	return &networkPeeringResource{}
+}
+
+type networkPeeringResource struct {
+	public_key  string
+	private_key string
+}
+
+type networkPeeringResourceModel struct {
+	ID                  types.String   `tfsdk:"id"`
+	ProjectID           types.String   `tfsdk:"project_id"`
+	ContainerID         types.String   `tfsdk:"container_id"`
+	CloudProvider       types.String   `tfsdk:"cloud_provider"`
+	AccepterRegionName  types.String   `tfsdk:"accepter_region_name"`
+	AWSAccountID        types.String   `tfsdk:"aws_account_id"`
+	RouteTableCIDRBlock types.String   `tfsdk:"route_table_cidr_block"`
+	VpcID               types.String   `tfsdk:"vpc_id"`
+	AzureDirectoryID    types.String   `tfsdk:"azure_directory_id"`
+	AzureSubscriptionID types.String   `tfsdk:"azure_subscription_id"`
+	ResourceGroupName   types.String   `tfsdk:"resource_group_name"`
+	VnetName            types.String   `tfsdk:"vnet_name"`
+	GCPProjectID        types.String   `tfsdk:"gcp_project_id"`
+	NetworkName         types.String   `tfsdk:"network_name"`
+	ConnectionID        types.String   `tfsdk:"connection_id"`
+	Status              types.String   `tfsdk:"status"`
+	ErrorMessage        types.String   `tfsdk:"error_message"`
+	Timeouts            timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *networkPeeringResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_networkpeering"
+}
+
+func (r *networkPeeringResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a MongoDB Atlas network peering connection between an Atlas network container and an AWS VPC, Azure VNet or GCP VPC",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the peering connection.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				Description: "MongoDB Atlas project identifier. Sometime referred to as group id.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(24),
+					stringvalidator.LengthAtMost(24),
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal project_id",
+					),
+				},
+			},
+			"container_id": schema.StringAttribute{
+				Description: "Identifier of the Atlas network container to peer, for example from the ids of the pgrmongodb_atlasclustercontainer data source.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^([a-f0-9]{24})$`),
+						"must be a valid 12 byte hexadecimal container_id",
+					),
+				},
+			},
+			"cloud_provider": schema.StringAttribute{
+				Description: "Cloud provider of the container and the peer network.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf([]string{"AWS", "GCP", "AZURE"}...),
+				},
+			},
+			"accepter_region_name": schema.StringAttribute{
+				Description: "AWS region of the peer VPC, for example us-east-1. Required for AWS.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"aws_account_id": schema.StringAttribute{
+				Description: "AWS account id of the owner of the peer VPC. Required for AWS.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^[0-9]{12}$`),
+						"must be a 12 digit AWS account id",
+					),
+				},
+			},
+			"route_table_cidr_block": schema.StringAttribute{
+				Description: "CIDR block of the peer VPC that Atlas routes to. Required for AWS.",
+				Optional:    true,
+			},
+			"vpc_id": schema.StringAttribute{
+				Description: "Identifier of the peer AWS VPC. Required for AWS.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"azure_directory_id": schema.StringAttribute{
+				Description: "Azure Active Directory tenant id of the peer VNet. Required for Azure.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"azure_subscription_id": schema.StringAttribute{
+				Description: "Azure subscription id of the peer VNet. Required for Azure.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"resource_group_name": schema.StringAttribute{
+				Description: "Azure resource group of the peer VNet. Required for Azure.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vnet_name": schema.StringAttribute{
+				Description: "Name of the peer Azure VNet. Required for Azure.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"gcp_project_id": schema.StringAttribute{
+				Description: "GCP project id of the peer VPC. Required for GCP.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"network_name": schema.StringAttribute{
+				Description: "Name of the peer GCP VPC network. Required for GCP.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"connection_id": schema.StringAttribute{
+				Description: "AWS VPC peering connection id to accept in the peer account. Only set for AWS.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Description: "Status of the peering connection. Apply waits for AVAILABLE, FAILED, PENDING_ACCEPTANCE or WAITING_FOR_USER.",
+				Computed:    true,
+			},
+			"error_message": schema.StringAttribute{
+				Description: "Error reported by Atlas for a failed peering connection. Null when the connection has no error.",
+				Computed:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *networkPeeringResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.public_key = req.ProviderData.(providerData).public_key
+	r.private_key = req.ProviderData.(providerData).private_key
+}
+
+func (r *networkPeeringResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config networkPeeringResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || config.CloudProvider.IsUnknown() {
+		return
+	}
+
+	cloudProvider := config.CloudProvider.ValueString()
+	attributes := config.providerAttributes()
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := attributes[name]
+		switch {
+		case value.provider == cloudProvider && value.value.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Missing Network Peering Attribute",
+				fmt.Sprintf("%s is required for %s network peering.", name, cloudProvider),
+			)
+		case value.provider != cloudProvider && !value.value.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Invalid Network Peering Attribute",
+				fmt.Sprintf("%s is only used for %s network peering, not %s.", name, value.provider, cloudProvider),
+			)
+		}
+	}
+}
+
+func (r *networkPeeringResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan networkPeeringResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, networkPeeringDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	projectID := plan.ProjectID.ValueString()
+
+	tflog.Info(ctx, "creating mongodb atlas network peering")
+	peering, err := saveNetworkPeering(r.public_key, r.private_key, projectID, "", plan.networkPeering())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating Network Peering",
+			"Could not create MongoDB Atlas network peering. Received error: "+err.Error(),
+		)
+		return
+	}
+	// the peering exists from here on, it is saved to state even when waiting fails so it is not orphaned
+	plan.ID = types.StringValue(peering.ID)
+	plan.fromNetworkPeering(peering)
+
+	peering, err = waitForNetworkPeering(ctx, r.public_key, r.private_key, projectID, peering.ID, peering.ProviderName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Creating Network Peering",
+			"Could not create MongoDB Atlas network peering. Received error: "+err.Error(),
+		)
+	} else {
+		plan.fromNetworkPeering(peering)
+		resp.Diagnostics.Append(networkPeeringStatusDiagnostics(peering)...)
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *networkPeeringResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state networkPeeringResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "reading mongodb atlas network peering")
+	peering, err := getNetworkPeering(r.public_key, r.private_key, state.ProjectID.ValueString(), state.ID.ValueString(), state.CloudProvider.ValueString())
+	if isDigestNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Network Peering",
+			"Could not read MongoDB Atlas network peering. Received error: "+err.Error(),
+		)
+		return
+	}
+
+	state.fromNetworkPeering(peering)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *networkPeeringResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan networkPeeringResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, networkPeeringDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	projectID := plan.ProjectID.ValueString()
+	peerID := plan.ID.ValueString()
+
+	tflog.Info(ctx, fmt.Sprintf("updating mongodb atlas network peering %s", peerID))
+	_, err := saveNetworkPeering(r.public_key, r.private_key, projectID, peerID, plan.networkPeering())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating Network Peering",
+			"Could not update MongoDB Atlas network peering. Received error: "+err.Error(),
+		)
+		return
+	}
+	peering, err := waitForNetworkPeering(ctx, r.public_key, r.private_key, projectID, peerID, plan.CloudProvider.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating Network Peering",
+			"Could not update MongoDB Atlas network peering. Received error: "+err.Error(),
+		)
+		return
+	}
+	plan.fromNetworkPeering(peering)
+	resp.Diagnostics.Append(networkPeeringStatusDiagnostics(peering)...)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *networkPeeringResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state networkPeeringResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, networkPeeringDefaultTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	projectID := state.ProjectID.ValueString()
+	peerID := state.ID.ValueString()
+
+	tflog.Info(ctx, fmt.Sprintf("deleting mongodb atlas network peering %s", peerID))
+	err := deleteNetworkPeering(r.public_key, r.private_key, projectID, peerID)
+	if isDigestNotFound(err) {
+		return
+	}
+	if err == nil {
+		err = waitForNetworkPeeringDeleted(ctx, r.public_key, r.private_key, projectID, peerID, state.CloudProvider.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Deleting Network Peering",
+			"Could not delete MongoDB Atlas network peering. Received error: "+err.Error(),
+		)
+		return
+	}
+}
+
+func (r *networkPeeringResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	idParts := strings.Split(req.ID, ",")
+
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Error Importing Network Peering",
+			"Could not import MongoDB Atlas network peering.\nPlease ensure you run terraform import with project_id,peering_id",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), idParts[1])...)
+}
+
+type networkPeeringProviderAttribute struct {
+	provider string
+	value    types.String
+}
+
+// returns the provider specific attributes by name with the cloud provider they belong to
+func (m networkPeeringResourceModel) providerAttributes() map[string]networkPeeringProviderAttribute {
+	return map[string]networkPeeringProviderAttribute{
+		"accepter_region_name":   {"AWS", m.AccepterRegionName},
+		"aws_account_id":         {"AWS", m.AWSAccountID},
+		"route_table_cidr_block": {"AWS", m.RouteTableCIDRBlock},
+		"vpc_id":                 {"AWS", m.VpcID},
+		"azure_directory_id":     {"AZURE", m.AzureDirectoryID},
+		"azure_subscription_id":  {"AZURE", m.AzureSubscriptionID},
+		"resource_group_name":    {"AZURE", m.ResourceGroupName},
+		"vnet_name":              {"AZURE", m.VnetName},
+		"gcp_project_id":         {"GCP", m.GCPProjectID},
+		"network_name":           {"GCP", m.NetworkName},
+	}
+}
+
+// returns the peering connection described by the plan
+func (m networkPeeringResourceModel) networkPeering() networkPeering {
+	return networkPeering{
+		ContainerID:         m.ContainerID.ValueString(),
+		ProviderName:        m.CloudProvider.ValueString(),
+		AccepterRegionName:  m.AccepterRegionName.ValueString(),
+		AWSAccountID:        m.AWSAccountID.ValueString(),
+		RouteTableCIDRBlock: m.RouteTableCIDRBlock.ValueString(),
+		VpcID:               m.VpcID.ValueString(),
+		AzureDirectoryID:    m.AzureDirectoryID.ValueString(),
+		AzureSubscriptionID: m.AzureSubscriptionID.ValueString(),
+		ResourceGroupName:   m.ResourceGroupName.ValueString(),
+		VnetName:            m.VnetName.ValueString(),
+		GCPProjectID:        m.GCPProjectID.ValueString(),
+		NetworkName:         m.NetworkName.ValueString(),
+	}
+}
+
+// copies the attributes Atlas reports for peering into the model
+func (m *networkPeeringResourceModel) fromNetworkPeering(peering networkPeering) {
+	m.ContainerID = types.StringValue(peering.ContainerID)
+	m.CloudProvider = types.StringValue(peering.ProviderName)
+	m.AccepterRegionName = optionalStringValue(peering.AccepterRegionName)
+	m.AWSAccountID = optionalStringValue(peering.AWSAccountID)
+	m.RouteTableCIDRBlock = optionalStringValue(peering.RouteTableCIDRBlock)
+	m.VpcID = optionalStringValue(peering.VpcID)
+	m.AzureDirectoryID = optionalStringValue(peering.AzureDirectoryID)
+	m.AzureSubscriptionID = optionalStringValue(peering.AzureSubscriptionID)
+	m.ResourceGroupName = optionalStringValue(peering.ResourceGroupName)
+	m.VnetName = optionalStringValue(peering.VnetName)
+	m.GCPProjectID = optionalStringValue(peering.GCPProjectID)
+	m.NetworkName = optionalStringValue(peering.NetworkName)
+	m.ConnectionID = optionalStringValue(peering.ConnectionID)
+	m.Status = types.StringValue(peering.Status)
+	m.ErrorMessage = optionalStringValue(peering.ErrorMessage)
+}
+
+// reports a failed peering as an error and a peering waiting on the peer network owner as a warning
+func networkPeeringStatusDiagnostics(peering networkPeering) diag.Diagnostics {
+	var diags diag.Diagnostics
+	switch peering.Status {
+	case "FAILED":
+		diags.AddError(
+			"Network Peering Failed",
+			fmt.Sprintf("MongoDB Atlas network peering %s failed: %s", peering.ID, peering.ErrorMessage),
+		)
+	case "PENDING_ACCEPTANCE":
+		diags.AddWarning(
+			"Network Peering Pending Acceptance",
+			fmt.Sprintf("Accept VPC peering connection %s in AWS account %s to finish MongoDB Atlas network peering %s.", peering.ConnectionID, peering.AWSAccountID, peering.ID),
+		)
+	case "WAITING_FOR_USER":
+		diags.AddWarning(
+			"Network Peering Waiting for User",
+			fmt.Sprintf("MongoDB Atlas network peering %s waits on the %s network owner, for example to create the peering from the %s side or grant Atlas access.", peering.ID, peering.ProviderName, peering.ProviderName),
+		)
+	}
+	return diags
+}
@@ -0,0 +1,104 @@
+package pgrmongodb
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// note this peers the AWS US_EAST_1 container of the project with a VPC that still has to accept the connection
+func TestAccPGRMongoDBNetworkPeeringResource(t *testing.T) {
+	project_id := "000000000000000000000000"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + testAccCheckPGRMongoDBNetworkPeeringConfig(project_id, "10.100.0.0/16"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("pgrmongodb_networkpeering.test", "id"),
+					resource.TestCheckResourceAttrPair("pgrmongodb_networkpeering.test", "container_id", "data.pgrmongodb_atlasclustercontainer.test", "ids.AWS:US_EAST_1"),
+					resource.TestCheckResourceAttr("pgrmongodb_networkpeering.test", "status", "PENDING_ACCEPTANCE"),
+					resource.TestCheckResourceAttrSet("pgrmongodb_networkpeering.test", "connection_id"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "pgrmongodb_networkpeering.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return project_id + "," + s.RootModule().Resources["pgrmongodb_networkpeering.test"].Primary.ID, nil
+				},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + testAccCheckPGRMongoDBNetworkPeeringConfig(project_id, "10.101.0.0/16"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pgrmongodb_networkpeering.test", "route_table_cidr_block", "10.101.0.0/16"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func TestAccPGRMongoDBNetworkPeeringResourceInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_networkpeering" "test" {
+	project_id = "000000000000000000000000"
+	container_id = "000000000000000000000000"
+	cloud_provider = "GCP"
+	gcp_project_id = "my-gcp-project"
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`network_name is required for GCP network peering`),
+			},
+			{
+				Config: providerConfig + `
+resource "pgrmongodb_networkpeering" "test" {
+	project_id = "000000000000000000000000"
+	container_id = "000000000000000000000000"
+	cloud_provider = "GCP"
+	gcp_project_id = "my-gcp-project"
+	network_name = "default"
+	vpc_id = "vpc-0123456789abcdef0"
+}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`vpc_id is only used for AWS network peering`),
+			},
+		},
+	})
+}
+
+func testAccCheckPGRMongoDBNetworkPeeringConfig(project_id string, route_table_cidr_block string) string {
+	return fmt.Sprintf(`
+		data "pgrmongodb_atlasclustercontainer" "test" {
+			project_id = "%[1]s"
+			cloud_provider = "AWS"
+		}
+
+		resource "pgrmongodb_networkpeering" "test" {
+			project_id = "%[1]s"
+			container_id = data.pgrmongodb_atlasclustercontainer.test.ids["AWS:US_EAST_1"]
+			cloud_provider = "AWS"
+			accepter_region_name = "us-east-1"
+			aws_account_id = "000000000000"
+			route_table_cidr_block = "%[2]s"
+			vpc_id = "vpc-0123456789abcdef0"
+
+			timeouts {
+				create = "15m"
+			}
+		}
+		`, project_id, route_table_cidr_block)
+}
@@ -0,0 +1,118 @@
+package pgrmongodb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = jsonStringValidator{}
+
+// validates that a string attribute holds a JSON encoded value
+type jsonStringValidator struct{}
+
+func (v jsonStringValidator) Description(_ context.Context) string {
+	return "value must be valid JSON"
+}
+
+func (v jsonStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonStringValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !json.Valid([]byte(req.ConfigValue.ValueString())) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid JSON Value",
+			"The value must be JSON encoded, for example with jsonencode(). Got: "+req.ConfigValue.ValueString(),
+		)
+	}
+}
+
+var _ validator.String = durationStringValidator{}
+
+// validates that a string attribute holds a Go duration such as 30s or 2m
+type durationStringValidator struct{}
+
+func (v durationStringValidator) Description(_ context.Context) string {
+	return "value must be a duration such as 30s or 2m"
+}
+
+func (v durationStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationStringValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	duration, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil || duration < 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration Value",
+			"The value must be a non-negative duration such as 30s or 2m. Got: "+req.ConfigValue.ValueString(),
+		)
+	}
+}
+
+var _ validator.String = jsonPathValidator{}
+
+// validates that a string attribute holds a JSONPath in the subset supported by lookupJSONPath
+type jsonPathValidator struct{}
+
+func (v jsonPathValidator) Description(_ context.Context) string {
+	return "value must be a JSONPath such as $.stats.migrated or $.items[0]"
+}
+
+func (v jsonPathValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonPathValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := parseJSONPath(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid JSONPath",
+			err.Error(),
+		)
+	}
+}
+
+var _ validator.String = npmVersionValidator{}
+
+// validates that a string attribute holds an npm version, semver range or dist-tag
+type npmVersionValidator struct{}
+
+func (v npmVersionValidator) Description(_ context.Context) string {
+	return "value must be an npm version, semver range or dist-tag such as 4.17.21, ^4.17.0 or latest"
+}
+
+func (v npmVersionValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v npmVersionValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !isValidNpmVersion(req.ConfigValue.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid npm Version",
+			"The value must be an npm version, semver range or dist-tag such as 4.17.21, ^4.17.0 or latest. Got: "+req.ConfigValue.ValueString(),
+		)
+	}
+}